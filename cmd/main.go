@@ -11,8 +11,18 @@ import (
 	"github.com/gofiber/fiber/v2"
 	_ "github.com/vk_intern/docs"
 	"github.com/vk_intern/internal/config"
+	"github.com/vk_intern/internal/feed"
 	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/matching"
+	"github.com/vk_intern/internal/middleware"
+	"github.com/vk_intern/internal/notify"
+	"github.com/vk_intern/internal/realtime"
 	"github.com/vk_intern/internal/repository"
+	"github.com/vk_intern/internal/revocation"
+	"github.com/vk_intern/internal/storage"
+	"github.com/vk_intern/internal/thumbnails"
+	"github.com/vk_intern/internal/users"
+	"github.com/vk_intern/internal/views"
 	"github.com/vk_intern/routes"
 )
 
@@ -21,6 +31,13 @@ func main() {
 
 	app := fiber.New(fiber.Config{
 		Prefork: false,
+		// запас сверх размера фотографии на остальные части multipart запроса
+		BodyLimit: cfg.Images.MaxSize + 1024*1024,
+		// IP адрес клиента берется из заголовка прокси, только если запрос пришел от доверенного прокси
+		ProxyHeader:             cfg.Server.ProxyHeader,
+		EnableTrustedProxyCheck: cfg.Server.ProxyHeader != "",
+		TrustedProxies:          cfg.Server.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
 	logger.Init("text")
@@ -40,6 +57,57 @@ func main() {
 		log.Fatal("Migration failed:", err)
 	}
 
-	routes.InitRoutes(app, cfg)
+	// выдача роли администратора пользователям из конфигурации, пока в сервисе нет ни одного
+	// администратора: дальше ролями управляют через API, и перезапуск не отменяет понижения
+	hasAdmin, err := repository.HasRole(ctx, users.RoleAdmin)
+	if err != nil {
+		log.Fatal("failed to check admins: ", err)
+	}
+	if !hasAdmin {
+		for _, login := range cfg.Admin.Logins {
+			if err := repository.SetUserRole(ctx, login, users.RoleAdmin); err != nil {
+				logger.L.Warn("failed to bootstrap admin", "login", login, "error", err)
+			}
+		}
+	}
+
+	// ключи подписи и проверки токенов
+	keys, err := middleware.NewKeySet(cfg.JWT.Algorithm, cfg.JWT.JWTsecret, cfg.JWT.SigningKeyID, cfg.JWT.PrivateKeys, cfg.JWT.PublicKeys, cfg.JWT.HS256Fallback)
+	if err != nil {
+		log.Fatal("failed to load JWT keys: ", err)
+	}
+
+	// доставка сообщений и уведомлений открытым WebSocket подключениям
+	hub := realtime.NewHub(cfg.Realtime.SubscriberBuffer)
+	notify.Init(hub)
+
+	// кеш отозванных токенов поверх БД, об отзыве сразу узнают открытые подключения
+	revocation.Init(ctx, cfg.JWT.RevocationCacheTTL, hub)
+
+	// хранилище загруженных фотографий
+	store, err := storage.NewLocalStorage(cfg.Images.Dir)
+	if err != nil {
+		log.Fatal("failed to init image storage: ", err)
+	}
+
+	// фоновая генерация миниатюр загруженных фотографий
+	thumbs := thumbnails.NewGenerator(store, cfg.Images.ThumbnailQueue, cfg.Images.MaxPixels)
+	thumbs.Run(ctx, cfg.Images.ThumbnailWorkers)
+
+	// поток свежих объявлений
+	stream := feed.NewFeed(cfg.Stream.SubscriberBuffer)
+	if err := stream.Run(ctx); err != nil {
+		log.Fatal("failed to start advertisement stream: ", err)
+	}
+
+	// фоновая сверка опубликованных объявлений с сохраненными поисками
+	matcher := matching.NewMatcher(cfg.Searches.MatchQueue)
+	matcher.Run(ctx, cfg.Searches.MatchWorkers)
+
+	// фоновая запись просмотров объявлений
+	recorder := views.NewRecorder(cfg.Views.Queue, cfg.Views.RetentionDays)
+	recorder.Run(ctx, cfg.Views.Workers)
+
+	routes.InitRoutes(app, cfg, keys, store, thumbs, matcher, hub, stream, recorder)
 	log.Fatal(app.Listen(cfg.Server.Port))
 }
@@ -15,6 +15,107 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Возвращает открытые ключи RS256/EdDSA, которыми можно проверить подпись access токенов, в формате JWKS. Ключ выбирается по kid из заголовка токена",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Публичные ключи подписи токенов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_middleware.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{login}/role": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Назначает пользователю роль user, moderator или admin, только для администраторов.\nРоль хранится в токене, поэтому все токены пользователя отзываются и ему нужно войти заново",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Смена роли пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Логин пользователя",
+                        "name": "login",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая роль",
+                        "name": "roleData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.SetRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.UserRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'forbidden'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/advertisements": {
             "get": {
                 "security": [
@@ -62,10 +163,16 @@ const docTemplate = `{
                         "name": "limit",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос по заголовку и описанию",
+                        "name": "q",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "default": "\"created_at\"",
-                        "description": "Параметр для сортировки",
+                        "description": "Параметр для сортировки: price, created_at, relevance (только вместе с q)",
                         "name": "order_by",
                         "in": "query"
                     },
@@ -75,16 +182,39 @@ const docTemplate = `{
                         "description": "Вид сортировки",
                         "name": "order",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Slug категории, включая все ее подкатегории",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "\"active\"",
+                        "description": "Статус объявлений: draft, pending, active, rejected, hidden, reserved, sold, archived (draft, pending, rejected, hidden и archived только свои)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор страницы: пустое значение для первой страницы, далее next_cursor из предыдущего ответа. При передаче page игнорируется",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "2",
+                        "description": "Версия API: 1 - ответ простым массивом объявлений (устаревший формат), 2 - ответ со страницей",
+                        "name": "X-API-Version",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementResponse"
-                            }
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementPage"
                         }
                     },
                     "400": {
@@ -109,7 +239,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Создает объявление, только для авторизированных пользователей",
+                "description": "Создает объявление, только для авторизированных пользователей.\nПри включенной премодерации объявление со статусом active создается в статусе pending и появится в ленте после одобрения модератором",
                 "consumes": [
                     "application/json"
                 ],
@@ -162,14 +292,74 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
-            "post": {
+        "/advertisements/stream": {
+            "get": {
+                "description": "Server-Sent Events: событие advertisement приходит сразу после публикации объявления, id события - номер публикации.\nУчитываются фильтры по цене и категории (вместе с подкатегориями). При переподключении с заголовком Last-Event-ID\nдосылаются все пропущенные публикации, которые все еще опубликованы",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "advertisements"
+                ],
+                "summary": "Поток свежих объявлений",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Минимальная цена",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальная цена",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Slug категории, включая подкатегории",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер последнего полученного события",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/advertisements/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Авторизирует зарегистрированного пользователя",
+                "description": "Возвращает одно объявление по его идентификатору вместе со всей галереей фотографий",
                 "consumes": [
                     "application/json"
                 ],
@@ -177,34 +367,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "advertisements"
                 ],
-                "summary": "Авторизация пользователя",
+                "summary": "Показать объявление",
                 "parameters": [
                     {
-                        "description": "Логин и пароль",
-                        "name": "loginData",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_vk_intern_internal_users.UserRequest"
-                        }
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "token",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementResponse"
                         }
                     },
-                    "208": {
-                        "description": "already authorized",
+                    "400": {
+                        "description": "'error': 'message'",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
+                    "404": {
                         "description": "'error': 'message'",
                         "schema": {
                             "type": "object",
@@ -219,11 +408,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/register": {
-            "post": {
-                "description": "Регистрирует нового пользователя",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет объявление, доступно только автору объявления",
                 "consumes": [
                     "application/json"
                 ],
@@ -231,28 +423,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "advertisements"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Удаление объявления",
                 "parameters": [
                     {
-                        "description": "Логин и пароль",
-                        "name": "registerData",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_vk_intern_internal_users.UserRequest"
-                        }
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
                         "schema": {
-                            "$ref": "#/definitions/github_com_vk_intern_internal_users.UserRegisterResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
                         "description": "'error': 'message'",
                         "schema": {
                             "type": "object",
@@ -267,56 +475,2851 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "github_com_vk_intern_internal_advertisements.Advertisement": {
-            "description": "Модель описывает объявление для возврата при его создании",
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string",
-                    "format": "date-time",
-                    "example": "2023-05-15T10:00:00Z"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "image_url": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "number"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "userlogin": {
-                    "type": "string"
-                }
-            }
-        },
-        "github_com_vk_intern_internal_advertisements.AdvertisementResponse": {
-            "description": "Модель описывает ответ на получение объявления",
-            "type": "object",
-            "properties": {
-                "created_at": {
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет переданные поля объявления, доступно только автору объявления.\nПри включенной премодерации изменение заголовка, описания, категории или фотографий активного объявления отправляет его на модерацию (pending)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "advertisements"
+                ],
+                "summary": "Изменение объявления",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля объявления",
+                        "name": "advertisementData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.UpdateAdvertisementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.Advertisement"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/advertisements/{id}/favorite": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет объявление в избранное пользователя, повторное добавление ничего не меняет",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favorites"
+                ],
+                "summary": "Добавление в избранное",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Убирает объявление из избранного пользователя, если оно там было",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favorites"
+                ],
+                "summary": "Удаление из избранного",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/advertisements/{id}/messages": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Покупатель пишет продавцу опубликованного объявления, первое сообщение начинает переписку.\nПродавец отвечает в существующую переписку, указав логин покупателя в buyer.\nПолучатель получает уведомление new_message, оба участника - событие message по WebSocket",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Сообщение по объявлению",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Текст сообщения",
+                        "name": "messageData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_messages.SendMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_messages.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/advertisements/{id}/reports": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отправляет жалобу на чужое опубликованное объявление, от одного пользователя принимается одна жалоба на объявление.\nНабравшее достаточно жалоб объявление в статусе active скрывается до решения модератора",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Жалоба на объявление",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина жалобы",
+                        "name": "reportData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_reports.CreateReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_reports.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/advertisements/{id}/reviews": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Оставляет оценку от 1 до 5 и комментарий продавцу объявления. Доступно покупателю,\nкоторому продавец ответил в переписке по этому объявлению, один отзыв на объявление",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Отзыв о продавце",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Оценка и комментарий",
+                        "name": "reviewData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_reviews.CreateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_reviews.Review"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/advertisements/{id}/status": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переводит объявление в новый статус, доступно только автору объявления.\nРазрешенные переходы: draft -\u003e active, archived; active -\u003e draft, reserved, sold, archived;\nreserved -\u003e active, sold, archived; sold -\u003e archived; archived -\u003e draft, active;\npending -\u003e draft, archived; rejected -\u003e draft, active, archived; hidden -\u003e archived.\nПри включенной премодерации публикация (active) черновика, архивного или отклоненного объявления отправляет его на модерацию (pending)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "advertisements"
+                ],
+                "summary": "Смена статуса объявления",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый статус",
+                        "name": "statusData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.ChangeStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.Advertisement"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "Возвращает все категории объявлений в виде дерева",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Дерево категорий",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_vk_intern_internal_categories.Category"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/images": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Загружает фотографию для объявления, только для авторизированных пользователей.\nФормат определяется по содержимому файла, поддерживаются jpeg, png и webp.\nРазрешение фотографии ограничено настройкой IMAGES_MAX_PIXELS.\nМиниатюры для jpeg и png создаются в фоне после загрузки",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Загрузка фотографии",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл фотографии",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.ImageUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{name}": {
+            "get": {
+                "description": "Отдает загруженную ранее фотографию",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/webp"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Получение фотографии",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя файла фотографии",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Авторизирует зарегистрированного пользователя и выдает пару токенов: короткоживущий access токен\nи долгоживущий refresh токен для его обновления. С заголовком X-API-Version: 1 возвращается только access токен строкой",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Авторизация пользователя",
+                "parameters": [
+                    {
+                        "description": "Логин и пароль",
+                        "name": "loginData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.UserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "2",
+                        "description": "Версия API",
+                        "name": "X-API-Version",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.TokenResponse"
+                        }
+                    },
+                    "208": {
+                        "description": "already authorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает текущий access токен. Если передан refresh токен, отзываются и все refresh токены,\nвыданные при том же входе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выход",
+                "parameters": [
+                    {
+                        "description": "Refresh токен",
+                        "name": "logoutData",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/logout/all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает все access и refresh токены пользователя, выданные до этого момента",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выход со всех устройств",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет переданные поля профиля текущего пользователя. Для способа связи phone в профиле должен быть телефон,\nбез способа связи messages покупатели не смогут начать переписку по объявлениям",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Изменение профиля",
+                "parameters": [
+                    {
+                        "description": "Изменяемые поля профиля",
+                        "name": "profileData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me/advertisements": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает объявления текущего пользователя во всех статусах, включая черновики, отклоненные и проданные,\nновые идут первыми. В отличие от ленты в каждом объявлении есть статистика stats: просмотры, избранное и переписки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "advertisements"
+                ],
+                "summary": "Мои объявления",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статус объявлений, по умолчанию все статусы",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementPage"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me/conversations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает переписки пользователя как покупателя и как продавца с превью последнего сообщения\nи количеством непрочитанных, сначала переписки с самыми свежими сообщениями",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Переписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_messages.ConversationPage"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me/conversations/{id}/messages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает сообщения переписки, новые идут первыми, и отмечает входящие прочитанными.\nДоступно только участникам переписки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Сообщения переписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор переписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_messages.MessagePage"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me/favorites": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает избранные объявления пользователя, последние добавленные идут первыми.\nОбъявления, которые сейчас скрыты от пользователя, не показываются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favorites"
+                ],
+                "summary": "Избранное",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementPage"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает уведомления пользователя, новые идут первыми, вместе с количеством непрочитанных.\nТипы уведомлений: saved_search_match, advertisement_approved, advertisement_rejected, favorite_price_drop, new_message",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Уведомления",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_notifications.NotificationPage"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications/read": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отмечает прочитанными переданные уведомления пользователя, без ids - все уведомления.\nВозвращает количество оставшихся непрочитанных",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Отметка уведомлений прочитанными",
+                "parameters": [
+                    {
+                        "description": "Идентификаторы уведомлений",
+                        "name": "readData",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_notifications.ReadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_notifications.ReadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me/saved-searches": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все сохраненные поиски пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved searches"
+                ],
+                "summary": "Сохраненные поиски",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_vk_intern_internal_searches.SavedSearch"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сохраняет параметры поиска объявлений. Новые опубликованные объявления, подходящие под поиск,\nпопадают в /me/saved-searches/matches. Пользователь может сохранить не более 20 поисков",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved searches"
+                ],
+                "summary": "Сохранение поиска",
+                "parameters": [
+                    {
+                        "description": "Параметры поиска",
+                        "name": "searchData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_searches.CreateSavedSearchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_searches.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me/saved-searches/matches": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает объявления, которые после публикации подошли под сохраненные поиски пользователя,\nновые совпадения идут первыми",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved searches"
+                ],
+                "summary": "Новые объявления по сохраненным поискам",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_searches.MatchedAdvertisementPage"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/me/saved-searches/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет сохраненный поиск вместе с найденными по нему объявлениями",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved searches"
+                ],
+                "summary": "Удаление сохраненного поиска",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор поиска",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/advertisements": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает объявления, ожидающие модерации, от самых старых к новым. Только для модераторов и администраторов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Очередь модерации",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementPage"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'forbidden'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/advertisements/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Публикует объявление из очереди модерации или скрытое по жалобам, жалобы на него закрываются. Только для модераторов и администраторов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Одобрение объявления",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.Advertisement"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'forbidden'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/advertisements/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отклоняет объявление из очереди модерации или скрытое по жалобам с причиной, которую увидит автор.\nЖалобы на объявление закрываются. Только для модераторов и администраторов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Отклонение объявления",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина отклонения",
+                        "name": "rejectData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.RejectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.Advertisement"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'forbidden'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/advertisements/{id}/reports/dismiss": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Закрывает жалобы на объявление, не меняя его статус. Скрытое по жалобам объявление нужно одобрить или отклонить.\nТолько для модераторов и администраторов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Отклонение жалоб",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'forbidden'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/reports": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает объявления с необработанными жалобами вместе с самими жалобами, сначала объявления с наибольшим числом жалоб.\nТолько для модераторов и администраторов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Жалобы по объявлениям",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_reports.ReportedAdvertisementPage"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "'error': 'forbidden'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Регистрирует нового пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Регистрация пользователя",
+                "parameters": [
+                    {
+                        "description": "Логин и пароль",
+                        "name": "registerData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.UserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.UserRegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/token/refresh": {
+            "post": {
+                "description": "Выдает новую пару токенов по refresh токену. Каждый refresh токен одноразовый:\nповторное предъявление использованного токена отзывает все токены, выданные при том же входе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Обновление токенов",
+                "parameters": [
+                    {
+                        "description": "Refresh токен",
+                        "name": "refreshData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{login}": {
+            "get": {
+                "description": "Возвращает публичный профиль пользователя с количеством опубликованных объявлений и оценкой как продавца.\nТелефон показывается, только если пользователь выбрал связь по телефону, владельцу профиля - всегда",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Профиль пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Логин пользователя",
+                        "name": "login",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_users.Profile"
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{login}/reviews": {
+            "get": {
+                "description": "Возвращает отзывы о пользователе как о продавце, новые идут первыми, вместе со средней оценкой и количеством отзывов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Отзывы о продавце",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Логин продавца",
+                        "name": "login",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_reviews.ReviewPage"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "WebSocket: сервер присылает события {\"type\": ..., \"payload\": ...} - message (новое сообщение в переписке),\nnotification (новое уведомление) и typing (собеседник набирает сообщение).\nКлиент может отправлять {\"type\": \"typing\", \"conversation_id\": 1}.\nТокен передается в заголовке Authorization или, из браузера, в параметре access_token.\nПодключение закрывается с кодом 1008, когда токен истекает или отзывается",
+                "tags": [
+                    "realtime"
+                ],
+                "summary": "События в реальном времени",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Access токен, если нельзя передать заголовок",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_realtime.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "'error': 'unauthorized'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "426": {
+                        "description": "'error': 'message'",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_vk_intern_internal_advertisements.Advertisement": {
+            "description": "Модель описывает объявление для возврата при его создании",
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementImage"
+                    }
+                },
+                "moderation_reason": {
+                    "description": "причина отклонения модератором, видна только автору объявления",
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "userlogin": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_advertisements.AdvertisementImage": {
+            "description": "Модель описывает фотографию из галереи объявления",
+            "type": "object",
+            "properties": {
+                "is_cover": {
+                    "type": "boolean"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_advertisements.AdvertisementPage": {
+            "description": "Модель описывает страницу объявлений вместе с общим количеством и признаком следующей страницы. page заполняется при постраничной выдаче, next_cursor - при выдаче по курсору",
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_advertisements.AdvertisementResponse": {
+            "description": "Модель описывает ответ на получение объявления",
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "images": {
+                    "description": "галерея заполняется только при получении одного объявления, в списках есть только обложка image_url",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementImage"
+                    }
+                },
+                "is_favorite": {
+                    "type": "boolean"
+                },
+                "ismine": {
+                    "type": "boolean"
+                },
+                "moderation_reason": {
+                    "description": "причина отклонения модератором, видна только автору объявления",
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "seller_rating": {
+                    "description": "средняя оценка продавца по отзывам покупателей, пустая, пока отзывов нет",
+                    "type": "number",
+                    "example": 4.67
+                },
+                "stats": {
+                    "description": "статистика объявления видна только его автору в списке своих объявлений",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_vk_intern_internal_advertisements.AdvertisementStats"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "thumbnails": {
+                    "description": "миниатюры обложки по размерам (small, medium, large), только для фотографий, загруженных в сервис",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "userlogin": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_advertisements.AdvertisementStats": {
+            "description": "Модель описывает статистику объявления, которую видит только его автор: просмотры карточки другими пользователями (не больше одного в день от пользователя или IP адреса), добавления в избранное и переписки с покупателями",
+            "type": "object",
+            "properties": {
+                "conversations": {
+                    "type": "integer"
+                },
+                "favorites": {
+                    "type": "integer"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_advertisements.ChangeStatusRequest": {
+            "description": "Модель описывает новый статус объявления: draft, active, reserved, sold, archived. При включенной премодерации публикация (active) отправляет объявление на проверку (pending)",
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "sold"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_advertisements.CreateAdvertisementRequest": {
+            "description": "Модель описывает запрос на создание объявления",
+            "type": "object",
+            "required": [
+                "category_id",
+                "description",
+                "images",
+                "price",
+                "title"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "cover_index": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "price": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "userLogin": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_advertisements.ImageUploadResponse": {
+            "description": "Модель описывает адрес загруженной фотографии для передачи в images объявления",
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string",
+                    "example": "/images/3f2a9c0d1b7e4e5f8a6b2c1d0e9f8a7b.jpg"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_advertisements.RejectRequest": {
+            "description": "Модель описывает причину отклонения объявления, которую увидит его автор",
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "фотографии не соответствуют описанию"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_advertisements.UpdateAdvertisementRequest": {
+            "description": "Модель описывает запрос на изменение объявления, передаются только изменяемые поля",
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "cover_index": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "price": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_categories.Category": {
+            "description": "Модель описывает категорию с вложенными подкатегориями",
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_categories.Category"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Телефоны"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "phones"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_messages.Conversation": {
+            "description": "Модель описывает переписку с превью последнего сообщения и количеством непрочитанных входящих",
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "integer"
+                },
+                "advertisement_title": {
+                    "type": "string"
+                },
+                "buyer": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_message": {
+                    "$ref": "#/definitions/github_com_vk_intern_internal_messages.Message"
+                },
+                "seller": {
+                    "type": "string"
+                },
+                "unread": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_messages.ConversationPage": {
+            "description": "Модель описывает страницу переписок, сначала идут переписки с самыми свежими сообщениями, unread - количество всех непрочитанных входящих сообщений",
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_messages.Conversation"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_messages.Message": {
+            "description": "Модель описывает сообщение, read_at заполняется, когда получатель открыл переписку",
+            "type": "object",
+            "properties": {
+                "conversation_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "read_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "sender": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_messages.MessagePage": {
+            "description": "Модель описывает страницу сообщений переписки, новые сообщения идут первыми",
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_messages.Message"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_messages.SendMessageRequest": {
+            "description": "Модель описывает текст сообщения. Покупатель пишет продавцу без buyer, продавец отвечает в переписку с покупателем buyer",
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "buyer": {
+                    "type": "string",
+                    "example": "buyer_login"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Здравствуйте, торг уместен?"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_middleware.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_middleware.JWKS": {
+            "description": "Модель описывает публичные ключи для проверки подписи токенов (RFC 7517)",
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_middleware.JWK"
+                    }
+                }
+            }
+        },
+        "github_com_vk_intern_internal_notifications.Notification": {
+            "description": "Модель описывает уведомление: type определяет событие, payload - его данные",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "read_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "saved_search_match"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_notifications.NotificationPage": {
+            "description": "Модель описывает страницу уведомлений, новые уведомления идут первыми, unread - количество всех непрочитанных",
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_notifications.Notification"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_notifications.ReadRequest": {
+            "description": "Модель описывает идентификаторы уведомлений, без них прочитанными отмечаются все уведомления",
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "github_com_vk_intern_internal_notifications.ReadResponse": {
+            "description": "Модель описывает количество оставшихся непрочитанных уведомлений",
+            "type": "object",
+            "properties": {
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_realtime.Event": {
+            "type": "object",
+            "properties": {
+                "payload": {},
+                "type": {
+                    "type": "string",
+                    "example": "message"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_reports.CreateReportRequest": {
+            "description": "Модель описывает причину жалобы (fraud, prohibited, wrong_category, duplicate, offensive, other) и необязательный комментарий, для причины other комментарий обязателен",
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "просит предоплату на карту"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "fraud"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_reports.Report": {
+            "description": "Модель описывает жалобу пользователя на объявление",
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
                     "type": "string",
                     "format": "date-time",
                     "example": "2023-05-15T10:00:00Z"
                 },
-                "description": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "image_url": {
+                "login": {
                     "type": "string"
                 },
-                "ismine": {
-                    "type": "boolean"
+                "reason": {
+                    "type": "string",
+                    "example": "fraud"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_reports.ReportedAdvertisement": {
+            "description": "Модель описывает объявление вместе со всеми необработанными жалобами на него",
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "integer"
                 },
-                "price": {
-                    "type": "number"
+                "last_reported_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_reports.Report"
+                    }
+                },
+                "reports_count": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "hidden"
                 },
                 "title": {
                     "type": "string"
@@ -326,30 +3329,347 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_vk_intern_internal_advertisements.CreateAdvertisementRequest": {
-            "description": "Модель описывает запрос на создание объявления",
+        "github_com_vk_intern_internal_reports.ReportedAdvertisementPage": {
+            "description": "Модель описывает страницу объявлений с жалобами, сначала идут объявления с наибольшим числом жалоб",
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_reports.ReportedAdvertisement"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_reviews.CreateReviewRequest": {
+            "description": "Модель описывает оценку продавца от 1 до 5 и необязательный комментарий",
             "type": "object",
             "required": [
-                "description",
-                "image_url",
-                "price",
-                "title"
+                "rating"
             ],
             "properties": {
-                "description": {
+                "comment": {
+                    "type": "string",
+                    "example": "вежливый продавец, все как в описании"
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "github_com_vk_intern_internal_reviews.Review": {
+            "description": "Модель описывает отзыв покупателя о продавце по объявлению, advertisement_id пустой, если объявление удалено",
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "integer"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "comment": {
                     "type": "string"
                 },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "seller": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_reviews.ReviewPage": {
+            "description": "Модель описывает страницу отзывов о продавце, новые идут первыми, вместе со средней оценкой rating и общим количеством отзывов total. rating пустой, пока отзывов нет",
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_reviews.Review"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 4.67
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_searches.CreateSavedSearchRequest": {
+            "description": "Модель описывает параметры поиска: диапазон цен, текст и slug категории (вместе с подкатегориями)",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "phones"
+                },
+                "max_price": {
+                    "type": "number",
+                    "example": 50000
+                },
+                "min_price": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "iPhone до 50000"
+                },
+                "q": {
+                    "type": "string",
+                    "example": "iphone"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_searches.MatchedAdvertisement": {
+            "description": "Модель описывает новое объявление, которое подошло под один из сохраненных поисков пользователя",
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "integer"
+                },
                 "image_url": {
                     "type": "string"
                 },
+                "matched_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                },
                 "price": {
                     "type": "number"
                 },
+                "saved_search_id": {
+                    "type": "integer"
+                },
+                "saved_search_name": {
+                    "type": "string",
+                    "example": "iPhone до 50000"
+                },
                 "title": {
                     "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_searches.MatchedAdvertisementPage": {
+            "description": "Модель описывает страницу совпадений по сохраненным поискам, новые совпадения идут первыми",
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
                 },
-                "userLogin": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_vk_intern_internal_searches.MatchedAdvertisement"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_searches.SavedSearch": {
+            "description": "Модель описывает сохраненный поиск, по которому отбираются новые объявления",
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "phones"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_price": {
+                    "type": "number",
+                    "example": 50000
+                },
+                "min_price": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "iPhone до 50000"
+                },
+                "q": {
+                    "type": "string",
+                    "example": "iphone"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_users.LogoutRequest": {
+            "description": "Модель описывает необязательный refresh токен, который нужно отозвать вместе с текущим access токеном",
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_users.Profile": {
+            "description": "Модель описывает публичный профиль пользователя со счетчиком опубликованных объявлений и оценкой как продавца. Телефон виден остальным, только если среди способов связи есть phone",
+            "type": "object",
+            "properties": {
+                "active_advertisements": {
+                    "type": "integer"
+                },
+                "avatar_url": {
+                    "type": "string"
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "contact_methods": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "messages",
+                        "phone"
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2023-05-15T10:00:00Z"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "login": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+79991234567"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 4.67
+                },
+                "reviews_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_users.RefreshRequest": {
+            "description": "Модель описывает запрос на получение новой пары токенов по refresh токену",
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_users.SetRoleRequest": {
+            "description": "Модель описывает новую роль пользователя: user, moderator или admin",
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "moderator"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_users.TokenResponse": {
+            "description": "Модель описывает access токен для заголовка Authorization и refresh токен для его обновления",
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "время действия access токена в секундах",
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_vk_intern_internal_users.UpdateProfileRequest": {
+            "description": "Модель описывает запрос на изменение профиля, передаются только изменяемые поля, пустая строка очищает поле. Способы связи: messages (сообщения по объявлениям), phone (телефон в профиле). Аватар - адрес фотографии, загруженной через POST /images",
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "example": "/images/3f2a9c1e.jpg"
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "contact_methods": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "display_name": {
                     "type": "string"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+79991234567"
                 }
             }
         },
@@ -382,6 +3702,18 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "github_com_vk_intern_internal_users.UserRoleResponse": {
+            "description": "Модель описывает логин пользователя и его текущую роль",
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/repository"
+	"github.com/vk_intern/internal/revocation"
+	"github.com/vk_intern/internal/users"
+)
+
+// SetUserRole godoc
+// @Summary Смена роли пользователя
+// @Description Назначает пользователю роль user, moderator или admin, только для администраторов.
+// @Description Роль хранится в токене, поэтому все токены пользователя отзываются и ему нужно войти заново
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param login path string true "Логин пользователя"
+// @Param roleData body users.SetRoleRequest true "Новая роль"
+// @Success 200 {object} users.UserRoleResponse
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'forbidden'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /admin/users/{login}/role [put]
+func SetUserRole(c *fiber.Ctx) error {
+	login := c.Params("login")
+
+	var req users.SetRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.L.Error("[SetUserRole | parse JSON]: failed parse req", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := users.ValidateRole(req.Role); err != nil {
+		logger.L.Error("[SetUserRole | validate]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "неизвестная роль, допустимы: user, moderator, admin"})
+	}
+
+	if err := repository.SetUserRole(context.Background(), login, req.Role); err != nil {
+		logger.L.Error("[SetUserRole | exec set role]:", "error", err)
+		if errors.Is(err, repository.ErrUserLoginWrong) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "пользователь не найден"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// старые токены содержат прежнюю роль
+	if err := revocation.RevokeAll(context.Background(), login, time.Now()); err != nil {
+		logger.L.Error("[SetUserRole | revoke tokens]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[SetUserRole]: success SetUserRole request")
+	return c.Status(fiber.StatusOK).JSON(users.UserRoleResponse{Login: login, Role: req.Role})
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/categories"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/repository"
+)
+
+// GetCategories godoc
+// @Summary Дерево категорий
+// @Description Возвращает все категории объявлений в виде дерева
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Success 200 {array} categories.Category
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /categories [get]
+func GetCategories(c *fiber.Ctx) error {
+	// запрос к БД
+	cats, err := repository.GetAllCategories(context.Background())
+	if err != nil {
+		logger.L.Error("[GetCategories | exec get categories]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetCategories]: success GetCategories request")
+	return c.Status(fiber.StatusOK).JSON(categories.BuildTree(cats))
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/repository"
+)
+
+// AddFavorite godoc
+// @Summary Добавление в избранное
+// @Description Добавляет объявление в избранное пользователя, повторное добавление ничего не меняет
+// @Security ApiKeyAuth
+// @Tags favorites
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Success 204
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements/{id}/favorite [put]
+func AddFavorite(c *fiber.Ctx) error {
+	// получим идентификатор объявления из пути
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		logger.L.Error("[AddFavorite | parse id]: failed parse id", "id", c.Params("id"))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[AddFavorite | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	if err := repository.AddFavorite(context.Background(), loginInterface.(string), id); err != nil {
+		logger.L.Error("[AddFavorite | exec add favorite]:", "error", err)
+		switch {
+		case errors.Is(err, repository.ErrAdvertisementNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "объявление не найдено"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	logger.L.Info("[AddFavorite]: success AddFavorite request")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RemoveFavorite godoc
+// @Summary Удаление из избранного
+// @Description Убирает объявление из избранного пользователя, если оно там было
+// @Security ApiKeyAuth
+// @Tags favorites
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Success 204
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements/{id}/favorite [delete]
+func RemoveFavorite(c *fiber.Ctx) error {
+	// получим идентификатор объявления из пути
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		logger.L.Error("[RemoveFavorite | parse id]: failed parse id", "id", c.Params("id"))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[RemoveFavorite | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	if err := repository.RemoveFavorite(context.Background(), loginInterface.(string), id); err != nil {
+		logger.L.Error("[RemoveFavorite | exec remove favorite]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[RemoveFavorite]: success RemoveFavorite request")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetFavorites godoc
+// @Summary Избранное
+// @Description Возвращает избранные объявления пользователя, последние добавленные идут первыми.
+// @Description Объявления, которые сейчас скрыты от пользователя, не показываются
+// @Security ApiKeyAuth
+// @Tags favorites
+// @Accept json
+// @Produce json
+// @Param page query integer false "Номер страницы" default(1)
+// @Param limit query integer false "Лимит на странице" default(10)
+// @Success 200 {object} advertisements.AdvertisementPage
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me/favorites [get]
+func GetFavorites(c *fiber.Ctx) error {
+	filter := advertisements.NewDefaultFilter()
+	if err := c.QueryParser(&filter); err != nil {
+		logger.L.Error("[GetFavorites | parse query]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := advertisements.ValidatePaginationInAdvertisementFilter(&filter); err != nil {
+		logger.L.Error("[GetFavorites | validate]:", "error", err)
+		switch {
+		case errors.Is(err, advertisements.ErrWrongPage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "номер страницы должен быть положительным"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "лимит на странице должен быть от 1 до 100"})
+		}
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[GetFavorites | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	page, err := repository.GetFavorites(context.Background(), loginInterface.(string), &filter)
+	if err != nil {
+		logger.L.Error("[GetFavorites | exec get favorites]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetFavorites]: success GetFavorites request")
+	return c.Status(fiber.StatusOK).JSON(page)
+}
@@ -5,13 +5,25 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/feed"
 	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/matching"
 	"github.com/vk_intern/internal/middleware"
+	"github.com/vk_intern/internal/notify"
 	"github.com/vk_intern/internal/repository"
+	"github.com/vk_intern/internal/revocation"
 	"github.com/vk_intern/internal/users"
+	"github.com/vk_intern/internal/views"
+)
+
+// заголовок для выбора версии формата ответов API
+const (
+	apiVersionHeader = "X-API-Version"
+	legacyAPIVersion = "1"
 )
 
 // RegisterUser godoc
@@ -79,18 +91,20 @@ func RegisterUser(c *fiber.Ctx) error {
 
 // LoginUser godoc
 // @Summary Авторизация пользователя
-// @Description Авторизирует зарегистрированного пользователя
+// @Description Авторизирует зарегистрированного пользователя и выдает пару токенов: короткоживущий access токен
+// @Description и долгоживущий refresh токен для его обновления. С заголовком X-API-Version: 1 возвращается только access токен строкой
 // @Security ApiKeyAuth
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param loginData body users.UserRequest true "Логин и пароль"
-// @Success 200 {string} string "token"
+// @Param X-API-Version header string false "Версия API" default(2)
+// @Success 200 {object} users.TokenResponse
 // @Success 208 {string} string "already authorized"
 // @Failure 400 {object} map[string]interface{} "'error': 'message'"
 // @Failure 500 {object}  map[string]interface{} "'error': 'message'"
 // @Router /login [post]
-func LoginUser(JWTsecret string) func(c *fiber.Ctx) error {
+func LoginUser(keys *middleware.KeySet, accessTTL, refreshTTL time.Duration) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 		var newUser users.UserRequest
 
@@ -115,89 +129,262 @@ func LoginUser(JWTsecret string) func(c *fiber.Ctx) error {
 			}
 		}
 
-		// создание JWT токена
-		token, err := middleware.GenerateJWTToken(newUser.Login, JWTsecret)
+		// в устаревшем формате отдаем только JWT токен
+		if c.Get(apiVersionHeader) == legacyAPIVersion {
+			token, err := generateAccessToken(newUser.Login, keys, accessTTL)
+			if err != nil {
+				logger.L.Error("[LoginUser | generateJWT]:", "error", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+
+			logger.L.Info("[LoginUser]: success LoginUser request")
+			return c.Status(fiber.StatusOK).JSON(token)
+		}
+
+		// при входе начинается новая цепочка refresh токенов
+		family, err := middleware.GenerateTokenFamily()
 		if err != nil {
-			logger.L.Error("[LoginUser | generateJWT]:", "error", err)
+			logger.L.Error("[LoginUser | generate family]:", "error", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		logger.L.Info("[LoginUser]: success LoginUser request")
-		return c.Status(fiber.StatusOK).JSON(token)
+		refreshToken, refreshHash, err := middleware.GenerateRefreshToken()
+		if err != nil {
+			logger.L.Error("[LoginUser | generate refresh]:", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if err := repository.SaveRefreshToken(context.Background(), newUser.Login, refreshHash, family, time.Now().Add(refreshTTL)); err != nil {
+			logger.L.Error("[LoginUser | save refresh]:", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		return sendTokens(c, "LoginUser", newUser.Login, refreshToken, keys, accessTTL)
 	}
 }
 
-// CreateAdvertisement godoc
-// @Summary Создание объявления
-// @Description Создает объявление, только для авторизированных пользователей
+// RefreshTokens godoc
+// @Summary Обновление токенов
+// @Description Выдает новую пару токенов по refresh токену. Каждый refresh токен одноразовый:
+// @Description повторное предъявление использованного токена отзывает все токены, выданные при том же входе
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refreshData body users.RefreshRequest true "Refresh токен"
+// @Success 200 {object} users.TokenResponse
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /token/refresh [post]
+func RefreshTokens(keys *middleware.KeySet, accessTTL, refreshTTL time.Duration) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		var req users.RefreshRequest
+
+		//парсим JSON в структуру запроса
+		if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+			logger.L.Error("[RefreshTokens | parse JSON]: failed parse req", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+		}
+
+		refreshToken, refreshHash, err := middleware.GenerateRefreshToken()
+		if err != nil {
+			logger.L.Error("[RefreshTokens | generate refresh]:", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		// погасим старый refresh токен и сохраним новый
+		oldHash := middleware.HashRefreshToken(req.RefreshToken)
+		login, err := repository.RotateRefreshToken(context.Background(), oldHash, refreshHash, time.Now().Add(refreshTTL))
+		if err != nil {
+			logger.L.Error("[RefreshTokens | rotate]:", "error", err)
+
+			switch {
+			case errors.Is(err, repository.ErrRefreshTokenInvalid):
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "недействительный refresh токен"})
+			case errors.Is(err, repository.ErrRefreshTokenExpired):
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "срок действия refresh токена истек, авторизуйтесь заново"})
+			case errors.Is(err, repository.ErrRefreshTokenReused):
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "refresh токен уже использован, все сессии этого входа завершены"})
+			default:
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		return sendTokens(c, "RefreshTokens", login, refreshToken, keys, accessTTL)
+	}
+}
+
+// Logout godoc
+// @Summary Выход
+// @Description Отзывает текущий access токен. Если передан refresh токен, отзываются и все refresh токены,
+// @Description выданные при том же входе
 // @Security ApiKeyAuth
-// @Tags advertisements
+// @Tags auth
 // @Accept json
 // @Produce json
-// @Param advertisementData body advertisements.CreateAdvertisementRequest true "Данные объявления"
-// @Success 201 {object} advertisements.Advertisement
+// @Param logoutData body users.LogoutRequest false "Refresh токен"
+// @Success 204
 // @Failure 400 {object} map[string]interface{} "'error': 'message'"
 // @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
 // @Failure 500 {object}  map[string]interface{} "'error': 'message'"
-// @Router /advertisements [post]
-func CreateAdvertisement(c *fiber.Ctx) error {
-	var newAdv advertisements.CreateAdvertisementRequest
+// @Router /logout [post]
+func Logout(c *fiber.Ctx) error {
+	var req users.LogoutRequest
 
-	//парсим JSON в структуру subscription
-	if err := c.BodyParser(&newAdv); err != nil {
-		logger.L.Error("[CreateAdvertisement | parse JSON]: failed parse newAdv", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	// тело запроса необязательное
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			logger.L.Error("[Logout | parse JSON]: failed parse req", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+		}
 	}
 
-	// провалидируем данные
-	err := advertisements.ValidateAdvertisement(&newAdv)
-	if err != nil {
-		logger.L.Error("[CreateAdvertisement | validate]:", "error", err)
+	// получим данные токена из контекста
+	claims, ok := c.Locals("claims").(*middleware.TokenClaims)
+	if !ok {
+		logger.L.Error("[Logout | get claims]: could not get claims from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
 
-		switch {
-		case errors.Is(err, advertisements.ErrShortTitle):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "заголовок должен содержать хотя бы 3 символа"})
-		case errors.Is(err, advertisements.ErrLongTitle):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "заголовк может содержать не более 50 символов"})
-		case errors.Is(err, advertisements.ErrWrongTitleSymbols):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "заголовок может содержать только буквы и цифры"})
-		case errors.Is(err, advertisements.ErrShortDescription):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "описание должно содержать хотя бы 1 символ"})
-		case errors.Is(err, advertisements.ErrLongDescription):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "описание может содержать не более 500 символов"})
-		case errors.Is(err, advertisements.ErrPriceLessZero):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может быть отрицательной"})
-		case errors.Is(err, advertisements.ErrBigPrice):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может превышать 100 000 000"})
-		case errors.Is(err, advertisements.ErrBigPricePrecision):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может содержать больше 2 знаков после запятой"})
-		case errors.Is(err, advertisements.ErrWrongURL):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный URL"})
-		case errors.Is(err, advertisements.ErrWrongImageFormat):
-			formats := strings.Join(advertisements.ImageFormats, " ")
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Поддерживаемые форматы: %s", formats)})
-		default:
+	if err := revocation.RevokeToken(context.Background(), claims.JTI, claims.Login, claims.ExpiresAt); err != nil {
+		logger.L.Error("[Logout | revoke access]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if req.RefreshToken != "" {
+		hash := middleware.HashRefreshToken(req.RefreshToken)
+		if err := repository.RevokeRefreshTokenFamily(context.Background(), claims.Login, hash); err != nil {
+			logger.L.Error("[Logout | revoke refresh]:", "error", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
 	}
 
+	logger.L.Info("[Logout]: success Logout request")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// LogoutAll godoc
+// @Summary Выход со всех устройств
+// @Description Отзывает все access и refresh токены пользователя, выданные до этого момента
+// @Security ApiKeyAuth
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /logout/all [post]
+func LogoutAll(c *fiber.Ctx) error {
 	// получим логин из контекста
 	loginInterface := c.Locals("login")
 	if loginInterface == nil {
-		logger.L.Error("[CreateAdvertisement | get login]: could not get login from token")
+		logger.L.Error("[LogoutAll | get login]: could not get login from token")
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
 	}
-	newAdv.UserLogin = loginInterface.(string)
 
-	// запрос к БД
-	respAdv, err := repository.LoadAdvertisement(context.Background(), &newAdv)
+	if err := revocation.RevokeAll(context.Background(), loginInterface.(string), time.Now()); err != nil {
+		logger.L.Error("[LogoutAll | revoke]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[LogoutAll]: success LogoutAll request")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// sendTokens выпускает access токен и отдает его клиенту вместе с refresh токеном
+func sendTokens(c *fiber.Ctx, handler, login, refreshToken string, keys *middleware.KeySet, accessTTL time.Duration) error {
+	token, err := generateAccessToken(login, keys, accessTTL)
 	if err != nil {
-		logger.L.Error("[CreateAdvertisement | exec create adv]:", "error", err)
+		logger.L.Error("["+handler+" | generateJWT]:", "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	logger.L.Info("[CreateAdvertisement]: success CreateAdvertisement request")
-	return c.Status(fiber.StatusCreated).JSON(respAdv)
+	logger.L.Info("[" + handler + "]: success " + handler + " request")
+	return c.Status(fiber.StatusOK).JSON(users.TokenResponse{
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(accessTTL.Seconds()),
+	})
+}
+
+// generateAccessToken выпускает access токен с текущей ролью пользователя из БД
+func generateAccessToken(login string, keys *middleware.KeySet, accessTTL time.Duration) (string, error) {
+	role, err := repository.GetUserRole(context.Background(), login)
+	if err != nil {
+		return "", fmt.Errorf("[generateAccessToken|get role]: %w", err)
+	}
+	return middleware.GenerateJWTToken(login, role, keys, accessTTL)
+}
+
+// CreateAdvertisement godoc
+// @Summary Создание объявления
+// @Description Создает объявление, только для авторизированных пользователей.
+// @Description При включенной премодерации объявление со статусом active создается в статусе pending и появится в ленте после одобрения модератором
+// @Security ApiKeyAuth
+// @Tags advertisements
+// @Accept json
+// @Produce json
+// @Param advertisementData body advertisements.CreateAdvertisementRequest true "Данные объявления"
+// @Success 201 {object} advertisements.Advertisement
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements [post]
+func CreateAdvertisement(premoderation bool, matcher *matching.Matcher, stream *feed.Feed) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		var newAdv advertisements.CreateAdvertisementRequest
+
+		//парсим JSON в структуру subscription
+		if err := c.BodyParser(&newAdv); err != nil {
+			logger.L.Error("[CreateAdvertisement | parse JSON]: failed parse newAdv", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+		}
+
+		// провалидируем данные
+		err := advertisements.ValidateAdvertisement(&newAdv)
+		if err != nil {
+			logger.L.Error("[CreateAdvertisement | validate]:", "error", err)
+			return advertisementValidationError(c, err)
+		}
+
+		// получим логин из контекста
+		loginInterface := c.Locals("login")
+		if loginInterface == nil {
+			logger.L.Error("[CreateAdvertisement | get login]: could not get login from token")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		}
+		newAdv.UserLogin = loginInterface.(string)
+
+		// при премодерации публикуемое объявление сначала попадает в очередь модерации
+		if premoderation {
+			newAdv.Status = advertisements.ApplyPremoderation("", newAdv.Status)
+		}
+
+		// запрос к БД
+		respAdv, err := repository.LoadAdvertisement(context.Background(), &newAdv)
+		if err != nil {
+			logger.L.Error("[CreateAdvertisement | exec create adv]:", "error", err)
+
+			switch {
+			case errors.Is(err, repository.ErrCategoryNotFound):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "категория не найдена"})
+			default:
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		// о новом опубликованном объявлении уведомим владельцев подходящих сохраненных поисков
+		// и подписчиков потока свежих объявлений
+		if respAdv.Status == advertisements.StatusActive {
+			matcher.Enqueue(respAdv.ID)
+			if err := stream.Publish(context.Background(), respAdv.ID); err != nil {
+				logger.L.Error("[CreateAdvertisement | publish]:", "error", err)
+			}
+		}
+
+		logger.L.Info("[CreateAdvertisement]: success CreateAdvertisement request")
+		return c.Status(fiber.StatusCreated).JSON(respAdv)
+	}
 }
 
 // GetAllAdvertisements godoc
@@ -211,9 +398,14 @@ func CreateAdvertisement(c *fiber.Ctx) error {
 // @Param min_price query number false "Минимальная цена" default(0)
 // @Param max_price query number false "Максимальная цена" default(100000000)
 // @Param limit query integer false "Лимит на странице" default(10)
-// @Param order_by query string false "Параметр для сортировки" default("created_at")
+// @Param q query string false "Поисковый запрос по заголовку и описанию"
+// @Param order_by query string false "Параметр для сортировки: price, created_at, relevance (только вместе с q)" default("created_at")
 // @Param order query string false "Вид сортировки" default("DESC")
-// @Success 200 {array} advertisements.AdvertisementResponse
+// @Param category query string false "Slug категории, включая все ее подкатегории"
+// @Param status query string false "Статус объявлений: draft, pending, active, rejected, hidden, reserved, sold, archived (draft, pending, rejected, hidden и archived только свои)" default("active")
+// @Param cursor query string false "Курсор страницы: пустое значение для первой страницы, далее next_cursor из предыдущего ответа. При передаче page игнорируется"
+// @Param X-API-Version header string false "Версия API: 1 - ответ простым массивом объявлений (устаревший формат), 2 - ответ со страницей" default(2)
+// @Success 200 {object} advertisements.AdvertisementPage
 // @Failure 400 {object} map[string]interface{} "'error': 'message'"
 // @Failure 500 {object}  map[string]interface{} "'error': 'message'"
 // @Router /advertisements [get]
@@ -234,6 +426,13 @@ func GetAllAdvertisements(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректные параметры фильтра"})
 	}
 
+	// наличие параметра cursor (даже пустого) включает выдачу по курсору
+	params.UseCursor = c.Context().QueryArgs().Has("cursor")
+
+	// в устаревшем формате ответа общее количество не нужно, кроме выдачи по курсору
+	legacy := c.Get(apiVersionHeader) == legacyAPIVersion && !params.UseCursor
+	params.WithTotal = !legacy
+
 	// провалидируем цены фильтрации
 	err := advertisements.ValidatePricesInAdverisementFilter(&params)
 	if err != nil {
@@ -251,13 +450,442 @@ func GetAllAdvertisements(c *fiber.Ctx) error {
 		}
 	}
 
+	// провалидируем сортировку и поисковый запрос
+	if err := advertisements.ValidateOrderInAdvertisementFilter(&params); err != nil {
+		logger.L.Error("[GetAllAdvertisements | validate]:", "error", err)
+
+		switch {
+		case errors.Is(err, advertisements.ErrWrongOrderBy):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "сортировать можно только по price, created_at или relevance"})
+		case errors.Is(err, advertisements.ErrWrongOrder):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "вид сортировки может быть только ASC или DESC"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+	if err := advertisements.ValidateSearchInAdvertisementFilter(&params); err != nil {
+		logger.L.Error("[GetAllAdvertisements | validate]:", "error", err)
+
+		switch {
+		case errors.Is(err, advertisements.ErrLongSearchQuery):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "поисковый запрос может содержать не более 200 символов"})
+		case errors.Is(err, advertisements.ErrRelevanceNoQuery):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "сортировка по релевантности доступна только вместе с поисковым запросом q"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	// провалидируем параметры пагинации
+	if err := advertisements.ValidatePaginationInAdvertisementFilter(&params); err != nil {
+		logger.L.Error("[GetAllAdvertisements | validate]:", "error", err)
+
+		switch {
+		case errors.Is(err, advertisements.ErrWrongPage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "номер страницы должен быть положительным"})
+		case errors.Is(err, advertisements.ErrWrongLimit):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "лимит на странице должен быть от 1 до 100"})
+		case errors.Is(err, advertisements.ErrCursorNotSupported):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "курсор доступен только при сортировке по price или created_at"})
+		case errors.Is(err, advertisements.ErrWrongCursor):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный курсор"})
+		case errors.Is(err, advertisements.ErrCursorParamsMismatch):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "курсор получен для другой сортировки"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	// провалидируем статус фильтрации
+	if err := advertisements.ValidateStatusInAdvertisementFilter(&params); err != nil {
+		logger.L.Error("[GetAllAdvertisements | validate]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "неизвестный статус объявления"})
+	}
+
+	// проверим, что категория фильтрации существует
+	if params.Category != "" {
+		exists, err := repository.CheckCategoryExists(context.Background(), params.Category)
+		if err != nil {
+			logger.L.Error("[GetAllAdvertisements | check category]:", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		if !exists {
+			logger.L.Error("[GetAllAdvertisements | check category]: category not found", "category", params.Category)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "категория не найдена"})
+		}
+	}
+
 	// запрос к БД
-	advs, err := repository.GetAllAdvertisements(context.Background(), login, &params)
+	page, err := repository.GetAllAdvertisements(context.Background(), login, &params)
 	if err != nil {
 		logger.L.Error("[GetAllAdvertisements | exec get all advs]:", "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	logger.L.Info("[GetAllAdvertisements]: success GetAllAdvertisements request")
-	return c.Status(fiber.StatusOK).JSON(advs)
+	if legacy {
+		return c.Status(fiber.StatusOK).JSON(page.Items)
+	}
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+// GetAdvertisement godoc
+// @Summary Показать объявление
+// @Description Возвращает одно объявление по его идентификатору вместе со всей галереей фотографий
+// @Security ApiKeyAuth
+// @Tags advertisements
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Success 200 {object} advertisements.AdvertisementResponse
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements/{id} [get]
+func GetAdvertisement(recorder *views.Recorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// получаем логин из контекста
+		var login string
+		loginInterface := c.Locals("login")
+		if loginInterface == nil {
+			login = ""
+		} else {
+			login = loginInterface.(string)
+		}
+
+		// получим идентификатор объявления из пути
+		id, err := c.ParamsInt("id")
+		if err != nil || id <= 0 {
+			logger.L.Error("[GetAdvertisement | parse id]: failed parse id", "id", c.Params("id"))
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+		}
+
+		// запрос к БД
+		adv, err := repository.GetAdvertisementByID(context.Background(), login, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrAdvertisementNotFound):
+				logger.L.Error("[GetAdvertisement | exec get adv]: advertisement not found", "id", id)
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "объявление не найдено"})
+			default:
+				logger.L.Error("[GetAdvertisement | exec get adv]:", "error", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		// просмотры автора в статистику объявления не попадают. Зритель - авторизованный пользователь,
+		// а для анонимных запросов IP адрес, чтобы повторные показы не накручивали счетчик
+		if !adv.IsMine {
+			viewer := "login:" + login
+			if login == "" {
+				viewer = "ip:" + c.IP()
+			}
+			recorder.Enqueue(id, viewer)
+		}
+
+		logger.L.Info("[GetAdvertisement]: success GetAdvertisement request")
+		return c.Status(fiber.StatusOK).JSON(adv)
+	}
+}
+
+// GetMyAdvertisements godoc
+// @Summary Мои объявления
+// @Description Возвращает объявления текущего пользователя во всех статусах, включая черновики, отклоненные и проданные,
+// @Description новые идут первыми. В отличие от ленты в каждом объявлении есть статистика stats: просмотры, избранное и переписки
+// @Security ApiKeyAuth
+// @Tags advertisements
+// @Accept json
+// @Produce json
+// @Param status query string false "Статус объявлений, по умолчанию все статусы"
+// @Param page query integer false "Номер страницы" default(1)
+// @Param limit query integer false "Лимит на странице" default(10)
+// @Success 200 {object} advertisements.AdvertisementPage
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me/advertisements [get]
+func GetMyAdvertisements(c *fiber.Ctx) error {
+	filter := advertisements.NewDefaultFilter()
+	// без статуса в запросе отдаем объявления во всех статусах
+	filter.Status = ""
+	if err := c.QueryParser(&filter); err != nil {
+		logger.L.Error("[GetMyAdvertisements | parse query]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := advertisements.ValidatePaginationInAdvertisementFilter(&filter); err != nil {
+		logger.L.Error("[GetMyAdvertisements | validate]:", "error", err)
+		switch {
+		case errors.Is(err, advertisements.ErrWrongPage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "номер страницы должен быть положительным"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "лимит на странице должен быть от 1 до 100"})
+		}
+	}
+
+	if filter.Status != "" {
+		if err := advertisements.ValidateStatusInAdvertisementFilter(&filter); err != nil {
+			logger.L.Error("[GetMyAdvertisements | validate]:", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "неизвестный статус объявления"})
+		}
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[GetMyAdvertisements | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	page, err := repository.GetMyAdvertisements(context.Background(), loginInterface.(string), filter.Status, filter.Page, filter.Limit)
+	if err != nil {
+		logger.L.Error("[GetMyAdvertisements | exec get advs]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetMyAdvertisements]: success GetMyAdvertisements request")
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+// UpdateAdvertisement godoc
+// @Summary Изменение объявления
+// @Description Изменяет переданные поля объявления, доступно только автору объявления.
+// @Description При включенной премодерации изменение заголовка, описания, категории или фотографий активного объявления отправляет его на модерацию (pending)
+// @Security ApiKeyAuth
+// @Tags advertisements
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Param advertisementData body advertisements.UpdateAdvertisementRequest true "Изменяемые поля объявления"
+// @Success 200 {object} advertisements.Advertisement
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'message'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements/{id} [patch]
+func UpdateAdvertisement(premoderation bool) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		// получим идентификатор объявления из пути
+		id, err := c.ParamsInt("id")
+		if err != nil || id <= 0 {
+			logger.L.Error("[UpdateAdvertisement | parse id]: failed parse id", "id", c.Params("id"))
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+		}
+
+		var updAdv advertisements.UpdateAdvertisementRequest
+
+		//парсим JSON в структуру изменений
+		if err := c.BodyParser(&updAdv); err != nil {
+			logger.L.Error("[UpdateAdvertisement | parse JSON]: failed parse updAdv", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+		}
+
+		// провалидируем только изменяемые поля
+		if err := advertisements.ValidateUpdateAdvertisement(&updAdv); err != nil {
+			logger.L.Error("[UpdateAdvertisement | validate]:", "error", err)
+			return advertisementValidationError(c, err)
+		}
+
+		// получим логин из контекста
+		loginInterface := c.Locals("login")
+		if loginInterface == nil {
+			logger.L.Error("[UpdateAdvertisement | get login]: could not get login from token")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		}
+
+		// запрос к БД
+		respAdv, oldPrice, err := repository.UpdateAdvertisement(context.Background(), loginInterface.(string), id, &updAdv, premoderation)
+		if err != nil {
+			if errors.Is(err, repository.ErrCategoryNotFound) {
+				logger.L.Error("[UpdateAdvertisement | exec]:", "error", err)
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "категория не найдена"})
+			}
+			if errors.Is(err, advertisements.ErrWrongCoverIndex) {
+				logger.L.Error("[UpdateAdvertisement | exec]:", "error", err)
+				return advertisementValidationError(c, err)
+			}
+			return advertisementOwnershipError(c, "UpdateAdvertisement", id, err)
+		}
+
+		// о снижении цены опубликованного объявления сообщим тем, кто добавил его в избранное
+		if respAdv.Price < oldPrice && advertisements.IsPublicStatus(respAdv.Status) {
+			if err := notify.FavoritePriceDrop(context.Background(), respAdv, oldPrice); err != nil {
+				logger.L.Error("[UpdateAdvertisement | notify price drop]:", "error", err)
+			}
+		}
+
+		logger.L.Info("[UpdateAdvertisement]: success UpdateAdvertisement request")
+		return c.Status(fiber.StatusOK).JSON(respAdv)
+	}
+}
+
+// DeleteAdvertisement godoc
+// @Summary Удаление объявления
+// @Description Удаляет объявление, доступно только автору объявления
+// @Security ApiKeyAuth
+// @Tags advertisements
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Success 204
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'message'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements/{id} [delete]
+func DeleteAdvertisement(c *fiber.Ctx) error {
+	// получим идентификатор объявления из пути
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		logger.L.Error("[DeleteAdvertisement | parse id]: failed parse id", "id", c.Params("id"))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[DeleteAdvertisement | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	if err := repository.DeleteAdvertisement(context.Background(), loginInterface.(string), id); err != nil {
+		return advertisementOwnershipError(c, "DeleteAdvertisement", id, err)
+	}
+
+	logger.L.Info("[DeleteAdvertisement]: success DeleteAdvertisement request")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ChangeAdvertisementStatus godoc
+// @Summary Смена статуса объявления
+// @Description Переводит объявление в новый статус, доступно только автору объявления.
+// @Description Разрешенные переходы: draft -> active, archived; active -> draft, reserved, sold, archived;
+// @Description reserved -> active, sold, archived; sold -> archived; archived -> draft, active;
+// @Description pending -> draft, archived; rejected -> draft, active, archived; hidden -> archived.
+// @Description При включенной премодерации публикация (active) черновика, архивного или отклоненного объявления отправляет его на модерацию (pending)
+// @Security ApiKeyAuth
+// @Tags advertisements
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Param statusData body advertisements.ChangeStatusRequest true "Новый статус"
+// @Success 200 {object} advertisements.Advertisement
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'message'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 409 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements/{id}/status [post]
+func ChangeAdvertisementStatus(premoderation bool, matcher *matching.Matcher, stream *feed.Feed) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		// получим идентификатор объявления из пути
+		id, err := c.ParamsInt("id")
+		if err != nil || id <= 0 {
+			logger.L.Error("[ChangeAdvertisementStatus | parse id]: failed parse id", "id", c.Params("id"))
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+		}
+
+		var req advertisements.ChangeStatusRequest
+
+		//парсим JSON в структуру запроса
+		if err := c.BodyParser(&req); err != nil {
+			logger.L.Error("[ChangeAdvertisementStatus | parse JSON]: failed parse req", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+		}
+
+		if err := advertisements.ValidateStatus(req.Status); err != nil {
+			logger.L.Error("[ChangeAdvertisementStatus | validate]:", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "неизвестный статус объявления"})
+		}
+
+		// получим логин из контекста
+		loginInterface := c.Locals("login")
+		if loginInterface == nil {
+			logger.L.Error("[ChangeAdvertisementStatus | get login]: could not get login from token")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		}
+
+		// запрос к БД
+		respAdv, err := repository.ChangeAdvertisementStatus(context.Background(), loginInterface.(string), id, req.Status, premoderation)
+		if err != nil {
+			if errors.Is(err, advertisements.ErrWrongStatusTransition) {
+				logger.L.Error("[ChangeAdvertisementStatus | exec]:", "error", err)
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "переход в этот статус из текущего запрещен"})
+			}
+			return advertisementOwnershipError(c, "ChangeAdvertisementStatus", id, err)
+		}
+
+		if respAdv.Status == advertisements.StatusActive {
+			matcher.Enqueue(respAdv.ID)
+			if err := stream.Publish(context.Background(), respAdv.ID); err != nil {
+				logger.L.Error("[ChangeAdvertisementStatus | publish]:", "error", err)
+			}
+		}
+
+		logger.L.Info("[ChangeAdvertisementStatus]: success ChangeAdvertisementStatus request")
+		return c.Status(fiber.StatusOK).JSON(respAdv)
+	}
+}
+
+// advertisementValidationError переводит ошибку валидации объявления в ответ клиенту
+func advertisementValidationError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, advertisements.ErrShortTitle):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "заголовок должен содержать хотя бы 3 символа"})
+	case errors.Is(err, advertisements.ErrLongTitle):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "заголовк может содержать не более 50 символов"})
+	case errors.Is(err, advertisements.ErrWrongTitleSymbols):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "заголовок может содержать только буквы и цифры"})
+	case errors.Is(err, advertisements.ErrShortDescription):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "описание должно содержать хотя бы 1 символ"})
+	case errors.Is(err, advertisements.ErrLongDescription):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "описание может содержать не более 500 символов"})
+	case errors.Is(err, advertisements.ErrPriceLessZero):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может быть отрицательной"})
+	case errors.Is(err, advertisements.ErrBigPrice):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может превышать 100 000 000"})
+	case errors.Is(err, advertisements.ErrBigPricePrecision):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может содержать больше 2 знаков после запятой"})
+	case errors.Is(err, advertisements.ErrWrongURL):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный URL"})
+	case errors.Is(err, advertisements.ErrWrongImageFormat):
+		formats := strings.Join(advertisements.ImageFormats, " ")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Поддерживаемые форматы: %s", formats)})
+	case errors.Is(err, advertisements.ErrNoImages):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "необходимо добавить хотя бы одну фотографию"})
+	case errors.Is(err, advertisements.ErrTooManyImages):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("объявление может содержать не более %d фотографий", advertisements.MaxImages)})
+	case errors.Is(err, advertisements.ErrWrongCoverIndex):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "обложка должна указывать на одну из фотографий объявления"})
+	case errors.Is(err, advertisements.ErrImagesAndURLBoth):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "нельзя одновременно передавать image_url и images"})
+	case errors.Is(err, advertisements.ErrEmptyUpdate):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "не переданы поля для изменения"})
+	case errors.Is(err, advertisements.ErrEmptyCategory):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "необходимо указать категорию объявления"})
+	case errors.Is(err, advertisements.ErrWrongInitStatus):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "новое объявление может быть только черновиком (draft) или активным (active)"})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
+
+// advertisementOwnershipError переводит ошибку изменения чужого или несуществующего объявления в ответ клиенту
+func advertisementOwnershipError(c *fiber.Ctx, handler string, id int, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrAdvertisementNotFound):
+		logger.L.Error("["+handler+" | exec]: advertisement not found", "id", id)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "объявление не найдено"})
+	case errors.Is(err, repository.ErrNotAdvertisementOwner):
+		logger.L.Error("["+handler+" | exec]: user is not the owner", "id", id)
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "изменять объявление может только его автор"})
+	default:
+		logger.L.Error("["+handler+" | exec]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
 }
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/storage"
+	"github.com/vk_intern/internal/thumbnails"
+)
+
+// UploadImage godoc
+// @Summary Загрузка фотографии
+// @Description Загружает фотографию для объявления, только для авторизированных пользователей.
+// @Description Формат определяется по содержимому файла, поддерживаются jpeg, png и webp.
+// @Description Разрешение фотографии ограничено настройкой IMAGES_MAX_PIXELS.
+// @Description Миниатюры для jpeg и png создаются в фоне после загрузки
+// @Security ApiKeyAuth
+// @Tags images
+// @Accept multipart/form-data
+// @Produce json
+// @Param image formData file true "Файл фотографии"
+// @Success 201 {object} advertisements.ImageUploadResponse
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 413 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /images [post]
+func UploadImage(store storage.Storage, thumbs *thumbnails.Generator, maxSize, maxPixels int) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		fileHeader, err := c.FormFile("image")
+		if err != nil {
+			logger.L.Error("[UploadImage | parse form]: failed get file", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "файл фотографии не передан в поле image"})
+		}
+
+		// проверим размер файла
+		if fileHeader.Size > int64(maxSize) {
+			logger.L.Error("[UploadImage | check size]: file is too large", "size", fileHeader.Size)
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": fmt.Sprintf("размер фотографии не может превышать %d байт", maxSize)})
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			logger.L.Error("[UploadImage | open file]:", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		defer file.Close()
+
+		// определим формат по первым байтам содержимого, расширение имени файла не учитываем
+		head := make([]byte, advertisements.ImageSniffLen)
+		n, err := io.ReadFull(file, head)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			logger.L.Error("[UploadImage | read file]:", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		head = head[:n]
+
+		ext, err := advertisements.DetectImageFormat(head)
+		if err != nil {
+			logger.L.Error("[UploadImage | detect format]:", "error", err)
+			formats := strings.Join(advertisements.ImageFormats, " ")
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Поддерживаемые форматы: %s", formats)})
+		}
+
+		// проверим разрешение по заголовку, не декодируя изображение целиком
+		err = advertisements.CheckImageResolution(io.MultiReader(bytes.NewReader(head), file), maxPixels)
+		if err != nil {
+			logger.L.Error("[UploadImage | check resolution]:", "error", err)
+			if errors.Is(err, advertisements.ErrImageTooLarge) {
+				return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": fmt.Sprintf("разрешение фотографии не может превышать %d пикселей", maxPixels)})
+			}
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "файл фотографии поврежден"})
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			logger.L.Error("[UploadImage | seek file]:", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		name, err := storage.RandomName(ext)
+		if err != nil {
+			logger.L.Error("[UploadImage | generate name]:", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		// сохраним файл в хранилище
+		if err := store.Save(context.Background(), name, file); err != nil {
+			logger.L.Error("[UploadImage | save file]:", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		// миниатюры создаются в фоне, до их появления вместо них отдается исходная фотография
+		thumbs.Enqueue(name)
+
+		logger.L.Info("[UploadImage]: success UploadImage request", "name", name)
+		return c.Status(fiber.StatusCreated).JSON(advertisements.ImageUploadResponse{URL: advertisements.ImagesURLPrefix + name})
+	}
+}
+
+// GetImage godoc
+// @Summary Получение фотографии
+// @Description Отдает загруженную ранее фотографию
+// @Tags images
+// @Produce image/jpeg,image/png,image/webp
+// @Param name path string true "Имя файла фотографии"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /images/{name} [get]
+func GetImage(store storage.Storage) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		name := c.Params("name")
+
+		file, err := store.Open(context.Background(), name)
+
+		// миниатюра еще не создана или не поддерживается для формата - отдаем исходную фотографию
+		cacheControl := "public, max-age=31536000, immutable"
+		if orig, isThumb := advertisements.ThumbnailOriginal(name); isThumb && errors.Is(err, storage.ErrNotFound) {
+			file, err = store.Open(context.Background(), orig)
+			cacheControl = "public, max-age=60"
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrInvalidName):
+				logger.L.Error("[GetImage | open file]: image not found", "name", name)
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "фотография не найдена"})
+			default:
+				logger.L.Error("[GetImage | open file]:", "error", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		// имена файлов случайные и не переиспользуются, поэтому фотографию можно кешировать навсегда
+		c.Set(fiber.HeaderContentType, advertisements.ImageContentType(strings.ToLower(filepath.Ext(name))))
+		c.Set(fiber.HeaderCacheControl, cacheControl)
+		return c.Status(fiber.StatusOK).SendStream(file)
+	}
+}
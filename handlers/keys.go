@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/middleware"
+)
+
+// GetJWKS godoc
+// @Summary Публичные ключи подписи токенов
+// @Description Возвращает открытые ключи RS256/EdDSA, которыми можно проверить подпись access токенов, в формате JWKS. Ключ выбирается по kid из заголовка токена
+// @Tags auth
+// @Produce json
+// @Success 200 {object} middleware.JWKS
+// @Router /.well-known/jwks.json [get]
+func GetJWKS(keys *middleware.KeySet) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		// ключи меняются только при перезапуске, но после ротации клиенты должны увидеть новый ключ быстро
+		c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return c.Status(fiber.StatusOK).JSON(keys.JWKS())
+	}
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/messages"
+	"github.com/vk_intern/internal/notify"
+	"github.com/vk_intern/internal/realtime"
+	"github.com/vk_intern/internal/repository"
+)
+
+// SendMessage godoc
+// @Summary Сообщение по объявлению
+// @Description Покупатель пишет продавцу опубликованного объявления, первое сообщение начинает переписку.
+// @Description Продавец отвечает в существующую переписку, указав логин покупателя в buyer.
+// @Description Получатель получает уведомление new_message, оба участника - событие message по WebSocket
+// @Security ApiKeyAuth
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Param messageData body messages.SendMessageRequest true "Текст сообщения"
+// @Success 201 {object} messages.Message
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'message'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements/{id}/messages [post]
+func SendMessage(pub realtime.PubSub) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		// получим идентификатор объявления из пути
+		id, err := c.ParamsInt("id")
+		if err != nil || id <= 0 {
+			logger.L.Error("[SendMessage | parse id]: failed parse id", "id", c.Params("id"))
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+		}
+
+		var req messages.SendMessageRequest
+
+		//парсим JSON в структуру запроса
+		if err := c.BodyParser(&req); err != nil {
+			logger.L.Error("[SendMessage | parse JSON]: failed parse req", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+		}
+
+		// провалидируем данные
+		if err := messages.ValidateMessage(&req); err != nil {
+			logger.L.Error("[SendMessage | validate]:", "error", err)
+			switch {
+			case errors.Is(err, messages.ErrEmptyText):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "сообщение не может быть пустым"})
+			default:
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "сообщение может содержать не более 2000 символов"})
+			}
+		}
+
+		// получим логин из контекста
+		loginInterface := c.Locals("login")
+		if loginInterface == nil {
+			logger.L.Error("[SendMessage | get login]: could not get login from token")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		}
+
+		// запрос к БД
+		conv, err := repository.SendMessage(context.Background(), loginInterface.(string), id, &req)
+		if err != nil {
+			logger.L.Error("[SendMessage | exec send message]:", "error", err)
+			switch {
+			case errors.Is(err, repository.ErrAdvertisementNotFound):
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "объявление не найдено"})
+			case errors.Is(err, repository.ErrConversationNotFound):
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "переписка с этим покупателем не найдена"})
+			case errors.Is(err, repository.ErrMessagesDisabled):
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "продавец не принимает сообщения, способы связи указаны в его профиле"})
+			case errors.Is(err, repository.ErrMessageOwnAdvertisement):
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "нельзя написать по своему объявлению, укажите покупателя в buyer"})
+			default:
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		// ошибка уведомления не отменяет отправку сообщения
+		if err := notify.NewMessage(context.Background(), conv); err != nil {
+			logger.L.Error("[SendMessage | notify recipient]:", "error", err)
+		}
+
+		// сообщение получают открытые подключения обоих участников, в том числе другие устройства отправителя
+		event := realtime.Event{Type: realtime.EventMessage, Payload: conv.LastMessage}
+		for _, login := range []string{conv.Buyer, conv.Seller} {
+			if err := pub.Publish(context.Background(), login, event); err != nil {
+				logger.L.Error("[SendMessage | publish message]:", "login", login, "error", err)
+			}
+		}
+
+		logger.L.Info("[SendMessage]: success SendMessage request")
+		return c.Status(fiber.StatusCreated).JSON(conv.LastMessage)
+	}
+}
+
+// GetConversations godoc
+// @Summary Переписки
+// @Description Возвращает переписки пользователя как покупателя и как продавца с превью последнего сообщения
+// @Description и количеством непрочитанных, сначала переписки с самыми свежими сообщениями
+// @Security ApiKeyAuth
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param page query integer false "Номер страницы" default(1)
+// @Param limit query integer false "Лимит на странице" default(10)
+// @Success 200 {object} messages.ConversationPage
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me/conversations [get]
+func GetConversations(c *fiber.Ctx) error {
+	filter := advertisements.NewDefaultFilter()
+	if err := c.QueryParser(&filter); err != nil {
+		logger.L.Error("[GetConversations | parse query]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := advertisements.ValidatePaginationInAdvertisementFilter(&filter); err != nil {
+		logger.L.Error("[GetConversations | validate]:", "error", err)
+		switch {
+		case errors.Is(err, advertisements.ErrWrongPage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "номер страницы должен быть положительным"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "лимит на странице должен быть от 1 до 100"})
+		}
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[GetConversations | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	page, err := repository.GetConversations(context.Background(), loginInterface.(string), filter.Page, filter.Limit)
+	if err != nil {
+		logger.L.Error("[GetConversations | exec get conversations]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetConversations]: success GetConversations request")
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+// GetMessages godoc
+// @Summary Сообщения переписки
+// @Description Возвращает сообщения переписки, новые идут первыми, и отмечает входящие прочитанными.
+// @Description Доступно только участникам переписки
+// @Security ApiKeyAuth
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор переписки"
+// @Param page query integer false "Номер страницы" default(1)
+// @Param limit query integer false "Лимит на странице" default(10)
+// @Success 200 {object} messages.MessagePage
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'message'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me/conversations/{id}/messages [get]
+func GetMessages(c *fiber.Ctx) error {
+	// получим идентификатор переписки из пути
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		logger.L.Error("[GetMessages | parse id]: failed parse id", "id", c.Params("id"))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор переписки"})
+	}
+
+	filter := advertisements.NewDefaultFilter()
+	if err := c.QueryParser(&filter); err != nil {
+		logger.L.Error("[GetMessages | parse query]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := advertisements.ValidatePaginationInAdvertisementFilter(&filter); err != nil {
+		logger.L.Error("[GetMessages | validate]:", "error", err)
+		switch {
+		case errors.Is(err, advertisements.ErrWrongPage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "номер страницы должен быть положительным"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "лимит на странице должен быть от 1 до 100"})
+		}
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[GetMessages | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	page, err := repository.GetMessages(context.Background(), loginInterface.(string), id, filter.Page, filter.Limit)
+	if err != nil {
+		logger.L.Error("[GetMessages | exec get messages]:", "error", err)
+		switch {
+		case errors.Is(err, repository.ErrConversationNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "переписка не найдена"})
+		case errors.Is(err, repository.ErrNotConversationParticipant):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "вы не участник этой переписки"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	logger.L.Info("[GetMessages]: success GetMessages request")
+	return c.Status(fiber.StatusOK).JSON(page)
+}
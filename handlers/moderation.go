@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/feed"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/matching"
+	"github.com/vk_intern/internal/notify"
+	"github.com/vk_intern/internal/repository"
+)
+
+// GetModerationQueue godoc
+// @Summary Очередь модерации
+// @Description Возвращает объявления, ожидающие модерации, от самых старых к новым. Только для модераторов и администраторов
+// @Security ApiKeyAuth
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param page query integer false "Номер страницы" default(1)
+// @Param limit query integer false "Лимит на странице" default(10)
+// @Success 200 {object} advertisements.AdvertisementPage
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'forbidden'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /moderation/advertisements [get]
+func GetModerationQueue(c *fiber.Ctx) error {
+	filter := advertisements.NewDefaultFilter()
+	if err := c.QueryParser(&filter); err != nil {
+		logger.L.Error("[GetModerationQueue | parse query]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := advertisements.ValidatePaginationInAdvertisementFilter(&filter); err != nil {
+		logger.L.Error("[GetModerationQueue | validate]:", "error", err)
+		switch {
+		case errors.Is(err, advertisements.ErrWrongPage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "номер страницы должен быть положительным"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "лимит на странице должен быть от 1 до 100"})
+		}
+	}
+
+	// запрос к БД
+	page, err := repository.GetPendingAdvertisements(context.Background(), &filter)
+	if err != nil {
+		logger.L.Error("[GetModerationQueue | exec get advs]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetModerationQueue]: success GetModerationQueue request")
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+// ApproveAdvertisement godoc
+// @Summary Одобрение объявления
+// @Description Публикует объявление из очереди модерации или скрытое по жалобам, жалобы на него закрываются. Только для модераторов и администраторов
+// @Security ApiKeyAuth
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Success 200 {object} advertisements.Advertisement
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'forbidden'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 409 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /moderation/advertisements/{id}/approve [post]
+func ApproveAdvertisement(matcher *matching.Matcher, stream *feed.Feed) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		return moderateAdvertisement(c, "ApproveAdvertisement", advertisements.StatusActive, "", matcher, stream)
+	}
+}
+
+// RejectAdvertisement godoc
+// @Summary Отклонение объявления
+// @Description Отклоняет объявление из очереди модерации или скрытое по жалобам с причиной, которую увидит автор.
+// @Description Жалобы на объявление закрываются. Только для модераторов и администраторов
+// @Security ApiKeyAuth
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Param rejectData body advertisements.RejectRequest true "Причина отклонения"
+// @Success 200 {object} advertisements.Advertisement
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'forbidden'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 409 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /moderation/advertisements/{id}/reject [post]
+func RejectAdvertisement(c *fiber.Ctx) error {
+	var req advertisements.RejectRequest
+
+	//парсим JSON в структуру запроса
+	if err := c.BodyParser(&req); err != nil {
+		logger.L.Error("[RejectAdvertisement | parse JSON]: failed parse req", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := advertisements.ValidateRejectReason(&req); err != nil {
+		logger.L.Error("[RejectAdvertisement | validate]:", "error", err)
+		switch {
+		case errors.Is(err, advertisements.ErrEmptyReason):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "необходимо указать причину отклонения"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "причина отклонения может содержать не более 500 символов"})
+		}
+	}
+
+	return moderateAdvertisement(c, "RejectAdvertisement", advertisements.StatusRejected, req.Reason, nil, nil)
+}
+
+// moderateAdvertisement применяет решение модератора к объявлению из пути запроса,
+// одобренное объявление сверяется с сохраненными поисками через matcher и попадает в поток stream
+func moderateAdvertisement(c *fiber.Ctx, handler, status, reason string, matcher *matching.Matcher, stream *feed.Feed) error {
+	// получим идентификатор объявления из пути
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		logger.L.Error("["+handler+" | parse id]: failed parse id", "id", c.Params("id"))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+	}
+
+	// получим логин модератора из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[" + handler + " | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	adv, err := repository.ModerateAdvertisement(context.Background(), loginInterface.(string), id, status, reason)
+	if err != nil {
+		logger.L.Error("["+handler+" | exec]:", "error", err)
+		switch {
+		case errors.Is(err, repository.ErrAdvertisementNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "объявление не найдено"})
+		case errors.Is(err, advertisements.ErrWrongStatusTransition):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "такое решение по объявлению в текущем статусе невозможно"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	if matcher != nil && adv.Status == advertisements.StatusActive {
+		matcher.Enqueue(adv.ID)
+	}
+	if stream != nil && adv.Status == advertisements.StatusActive {
+		if err := stream.Publish(context.Background(), adv.ID); err != nil {
+			logger.L.Error("["+handler+" | publish]:", "error", err)
+		}
+	}
+
+	// сообщим автору о решении модератора, ошибка уведомления не отменяет само решение
+	switch adv.Status {
+	case advertisements.StatusActive:
+		err = notify.AdvertisementApproved(context.Background(), adv)
+	case advertisements.StatusRejected:
+		err = notify.AdvertisementRejected(context.Background(), adv)
+	}
+	if err != nil {
+		logger.L.Error("["+handler+" | notify author]:", "error", err)
+	}
+
+	logger.L.Info("[" + handler + "]: success " + handler + " request")
+	return c.Status(fiber.StatusOK).JSON(adv)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/notifications"
+	"github.com/vk_intern/internal/repository"
+)
+
+// GetNotifications godoc
+// @Summary Уведомления
+// @Description Возвращает уведомления пользователя, новые идут первыми, вместе с количеством непрочитанных.
+// @Description Типы уведомлений: saved_search_match, advertisement_approved, advertisement_rejected, favorite_price_drop, new_message
+// @Security ApiKeyAuth
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param page query integer false "Номер страницы" default(1)
+// @Param limit query integer false "Лимит на странице" default(10)
+// @Success 200 {object} notifications.NotificationPage
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me/notifications [get]
+func GetNotifications(c *fiber.Ctx) error {
+	filter := advertisements.NewDefaultFilter()
+	if err := c.QueryParser(&filter); err != nil {
+		logger.L.Error("[GetNotifications | parse query]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := advertisements.ValidatePaginationInAdvertisementFilter(&filter); err != nil {
+		logger.L.Error("[GetNotifications | validate]:", "error", err)
+		switch {
+		case errors.Is(err, advertisements.ErrWrongPage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "номер страницы должен быть положительным"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "лимит на странице должен быть от 1 до 100"})
+		}
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[GetNotifications | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	page, err := repository.GetNotifications(context.Background(), loginInterface.(string), filter.Page, filter.Limit)
+	if err != nil {
+		logger.L.Error("[GetNotifications | exec get notifications]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetNotifications]: success GetNotifications request")
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+// ReadNotifications godoc
+// @Summary Отметка уведомлений прочитанными
+// @Description Отмечает прочитанными переданные уведомления пользователя, без ids - все уведомления.
+// @Description Возвращает количество оставшихся непрочитанных
+// @Security ApiKeyAuth
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param readData body notifications.ReadRequest false "Идентификаторы уведомлений"
+// @Success 200 {object} notifications.ReadResponse
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me/notifications/read [post]
+func ReadNotifications(c *fiber.Ctx) error {
+	var req notifications.ReadRequest
+
+	// тело запроса необязательное
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			logger.L.Error("[ReadNotifications | parse JSON]: failed parse req", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+		}
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[ReadNotifications | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	unread, err := repository.MarkNotificationsRead(context.Background(), loginInterface.(string), req.IDs)
+	if err != nil {
+		logger.L.Error("[ReadNotifications | exec mark read]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[ReadNotifications]: success ReadNotifications request")
+	return c.Status(fiber.StatusOK).JSON(notifications.ReadResponse{Unread: unread})
+}
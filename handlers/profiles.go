@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/repository"
+	"github.com/vk_intern/internal/users"
+)
+
+// GetProfile godoc
+// @Summary Профиль пользователя
+// @Description Возвращает публичный профиль пользователя с количеством опубликованных объявлений и оценкой как продавца.
+// @Description Телефон показывается, только если пользователь выбрал связь по телефону, владельцу профиля - всегда
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param login path string true "Логин пользователя"
+// @Success 200 {object} users.Profile
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /users/{login} [get]
+func GetProfile(c *fiber.Ctx) error {
+	login := c.Params("login")
+
+	// владелец профиля видит свой телефон в любом случае
+	loginInterface := c.Locals("login")
+	owner := loginInterface != nil && loginInterface.(string) == login
+
+	// запрос к БД
+	profile, err := repository.GetProfile(context.Background(), login, owner)
+	if err != nil {
+		logger.L.Error("[GetProfile | exec get profile]:", "error", err)
+		if errors.Is(err, repository.ErrUserLoginWrong) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "пользователь не найден"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetProfile]: success GetProfile request")
+	return c.Status(fiber.StatusOK).JSON(profile)
+}
+
+// UpdateProfile godoc
+// @Summary Изменение профиля
+// @Description Изменяет переданные поля профиля текущего пользователя. Для способа связи phone в профиле должен быть телефон,
+// @Description без способа связи messages покупатели не смогут начать переписку по объявлениям
+// @Security ApiKeyAuth
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param profileData body users.UpdateProfileRequest true "Изменяемые поля профиля"
+// @Success 200 {object} users.Profile
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me [patch]
+func UpdateProfile(c *fiber.Ctx) error {
+	var upd users.UpdateProfileRequest
+
+	//парсим JSON в структуру запроса
+	if err := c.BodyParser(&upd); err != nil {
+		logger.L.Error("[UpdateProfile | parse JSON]: failed parse req", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	// провалидируем данные
+	if err := users.ValidateUpdateProfile(&upd); err != nil {
+		logger.L.Error("[UpdateProfile | validate]:", "error", err)
+		return profileValidationError(c, err)
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[UpdateProfile | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	profile, err := repository.UpdateProfile(context.Background(), loginInterface.(string), &upd)
+	if err != nil {
+		logger.L.Error("[UpdateProfile | exec update profile]:", "error", err)
+		switch {
+		case errors.Is(err, users.ErrPhoneRequired):
+			return profileValidationError(c, err)
+		case errors.Is(err, repository.ErrUserLoginWrong):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	logger.L.Info("[UpdateProfile]: success UpdateProfile request")
+	return c.Status(fiber.StatusOK).JSON(profile)
+}
+
+// profileValidationError переводит ошибку проверки профиля в ответ клиенту
+func profileValidationError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, users.ErrEmptyProfileUpdate):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "нет полей для изменения"})
+	case errors.Is(err, users.ErrLongDisplayName):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "имя может содержать не более 50 символов"})
+	case errors.Is(err, users.ErrWrongAvatarURL):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "аватар должен быть ссылкой вида /images/... на загруженную фотографию в формате jpg, jpeg, png или webp"})
+	case errors.Is(err, users.ErrLongBio):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "описание может содержать не более 1000 символов"})
+	case errors.Is(err, users.ErrLongCity):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "город может содержать не более 100 символов"})
+	case errors.Is(err, users.ErrWrongPhone):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "телефон должен содержать от 10 до 15 цифр"})
+	case errors.Is(err, users.ErrUnknownContactMethod):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "неизвестный способ связи, допустимы: messages, phone"})
+	case errors.Is(err, users.ErrEmptyContactMethods):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "нужен хотя бы один способ связи"})
+	case errors.Is(err, users.ErrPhoneRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "для связи по телефону укажите телефон"})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/middleware"
+	"github.com/vk_intern/internal/realtime"
+	"github.com/vk_intern/internal/repository"
+	"github.com/vk_intern/internal/revocation"
+)
+
+const (
+	// сколько ждать записи в подключение
+	wsWriteWait = 10 * time.Second
+	// сколько ждать ответа клиента на ping, после этого подключение считается оборванным
+	wsPongWait = 60 * time.Second
+	// как часто отправлять ping, должно быть меньше wsPongWait
+	wsPingPeriod = wsPongWait * 9 / 10
+	// максимальный размер события от клиента
+	wsMaxMessageSize = 1024
+	// как часто пересылать собеседнику typing по одной переписке
+	wsTypingInterval = 3 * time.Second
+)
+
+// Realtime godoc
+// @Summary События в реальном времени
+// @Description WebSocket: сервер присылает события {"type": ..., "payload": ...} - message (новое сообщение в переписке),
+// @Description notification (новое уведомление) и typing (собеседник набирает сообщение).
+// @Description Клиент может отправлять {"type": "typing", "conversation_id": 1}.
+// @Description Токен передается в заголовке Authorization или, из браузера, в параметре access_token.
+// @Description Подключение закрывается с кодом 1008, когда токен истекает или отзывается
+// @Security ApiKeyAuth
+// @Tags realtime
+// @Param access_token query string false "Access токен, если нельзя передать заголовок"
+// @Success 101 {object} realtime.Event
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 426 {object} map[string]interface{} "'error': 'message'"
+// @Router /ws [get]
+func Realtime(pub realtime.PubSub) fiber.Handler {
+	return websocket.New(func(conn *websocket.Conn) {
+		login, _ := conn.Locals("login").(string)
+		claims, ok := conn.Locals("claims").(*middleware.TokenClaims)
+		if !ok {
+			return
+		}
+
+		sub := pub.Subscribe(login)
+		defer sub.Close()
+
+		// читаем события клиента в отдельной горутине, писать в подключение будет только основная
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			readClientEvents(conn, login, pub)
+		}()
+
+		ping := time.NewTicker(wsPingPeriod)
+		defer ping.Stop()
+
+		// токен проверялся только при подключении, поэтому по его истечении подключение закрываем
+		expire := time.NewTimer(time.Until(claims.ExpiresAt))
+		defer expire.Stop()
+
+		// отзыв токена (выход, блокировка) должен закрывать и уже открытые подключения
+		revoked := func() bool {
+			revoked, err := revocation.IsRevoked(context.Background(), claims.JTI, claims.Login, claims.IssuedAt)
+			if err != nil {
+				logger.L.Error("[Realtime | check revoked]:", "login", login, "error", err)
+				return false
+			}
+			return revoked
+		}
+
+		for {
+			select {
+			case <-done:
+				return
+			case event, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				// об отзыве сообщает сервис, клиенту это событие не пересылается
+				if event.Type == realtime.EventRevoked {
+					if revoked() {
+						closeWithPolicyViolation(conn, "token revoked")
+						return
+					}
+					continue
+				}
+				conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+				if err := conn.WriteJSON(event); err != nil {
+					logger.L.Error("[Realtime | write event]:", "login", login, "error", err)
+					return
+				}
+			case <-expire.C:
+				closeWithPolicyViolation(conn, "token expired")
+				return
+			case <-ping.C:
+				// отзыв на другом экземпляре сервиса сюда не приходит, поэтому токен перепроверяется и по таймеру
+				if revoked() {
+					closeWithPolicyViolation(conn, "token revoked")
+					return
+				}
+
+				conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// closeWithPolicyViolation сообщает клиенту причину закрытия подключения
+func closeWithPolicyViolation(conn *websocket.Conn, reason string) {
+	msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+	if err := conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteWait)); err != nil {
+		logger.L.Error("[closeWithPolicyViolation | write close]:", "error", err)
+	}
+}
+
+// readClientEvents читает события клиента до закрытия подключения
+func readClientEvents(conn *websocket.Conn, login string, pub realtime.PubSub) {
+	conn.SetReadLimit(wsMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	typing := &typingState{peers: map[int]string{}, sent: map[int]time.Time{}}
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		// некорректное событие не повод рвать подключение
+		var event realtime.ClientEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			logger.L.Error("[readClientEvents | parse JSON]: failed parse event", "login", login, "error", err)
+			continue
+		}
+
+		switch event.Type {
+		case realtime.EventTyping:
+			if err := typing.publish(login, event.ConversationID, pub); err != nil {
+				logger.L.Error("[readClientEvents | publish typing]:", "login", login, "error", err)
+			}
+		default:
+			logger.L.Error("[readClientEvents]: unknown event type", "login", login, "type", event.Type)
+		}
+	}
+}
+
+// typingState собеседники и время последнего typing по перепискам одного подключения
+type typingState struct {
+	peers map[int]string // "" - переписка пользователю недоступна
+	sent  map[int]time.Time
+}
+
+// publish сообщает собеседнику, что пользователь набирает сообщение в переписке id. Клиенты шлют
+// typing на каждое нажатие клавиши, поэтому по одной переписке событие пересылается не чаще
+// wsTypingInterval, а собеседник ищется в БД один раз за подключение
+func (t *typingState) publish(login string, id int, pub realtime.PubSub) error {
+	now := time.Now()
+	if now.Sub(t.sent[id]) < wsTypingInterval {
+		return nil
+	}
+	t.sent[id] = now
+
+	peer, ok := t.peers[id]
+	if !ok {
+		var err error
+		peer, err = repository.GetConversationPeer(context.Background(), login, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrConversationNotFound) || errors.Is(err, repository.ErrNotConversationParticipant) {
+				// чужие переписки для пользователя не существуют
+				t.peers[id] = ""
+				return nil
+			}
+			return err
+		}
+		t.peers[id] = peer
+	}
+	if peer == "" {
+		return nil
+	}
+
+	event := realtime.Event{
+		Type:    realtime.EventTyping,
+		Payload: realtime.TypingPayload{ConversationID: id, Login: login},
+	}
+	return pub.Publish(context.Background(), peer, event)
+}
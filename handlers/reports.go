@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/reports"
+	"github.com/vk_intern/internal/repository"
+)
+
+// CreateReport godoc
+// @Summary Жалоба на объявление
+// @Description Отправляет жалобу на чужое опубликованное объявление, от одного пользователя принимается одна жалоба на объявление.
+// @Description Набравшее достаточно жалоб объявление в статусе active скрывается до решения модератора
+// @Security ApiKeyAuth
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Param reportData body reports.CreateReportRequest true "Причина жалобы"
+// @Success 201 {object} reports.Report
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'message'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 409 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements/{id}/reports [post]
+func CreateReport(hideThreshold int) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		// получим идентификатор объявления из пути
+		id, err := c.ParamsInt("id")
+		if err != nil || id <= 0 {
+			logger.L.Error("[CreateReport | parse id]: failed parse id", "id", c.Params("id"))
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+		}
+
+		var req reports.CreateReportRequest
+
+		//парсим JSON в структуру запроса
+		if err := c.BodyParser(&req); err != nil {
+			logger.L.Error("[CreateReport | parse JSON]: failed parse req", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+		}
+
+		// провалидируем данные
+		if err := reports.ValidateReport(&req); err != nil {
+			logger.L.Error("[CreateReport | validate]:", "error", err)
+			switch {
+			case errors.Is(err, reports.ErrUnknownReason):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "неизвестная причина жалобы, допустимы: fraud, prohibited, wrong_category, duplicate, offensive, other"})
+			case errors.Is(err, reports.ErrEmptyComment):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "для причины other необходимо описать проблему в комментарии"})
+			default:
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "комментарий может содержать не более 500 символов"})
+			}
+		}
+
+		// получим логин из контекста
+		loginInterface := c.Locals("login")
+		if loginInterface == nil {
+			logger.L.Error("[CreateReport | get login]: could not get login from token")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		}
+
+		// запрос к БД
+		report, err := repository.CreateReport(context.Background(), loginInterface.(string), id, &req, hideThreshold)
+		if err != nil {
+			logger.L.Error("[CreateReport | exec create report]:", "error", err)
+			switch {
+			case errors.Is(err, repository.ErrAdvertisementNotFound):
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "объявление не найдено"})
+			case errors.Is(err, repository.ErrReportOwnAdvertisement):
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "нельзя пожаловаться на свое объявление"})
+			case errors.Is(err, repository.ErrReportExists):
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "вы уже пожаловались на это объявление"})
+			default:
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		logger.L.Info("[CreateReport]: success CreateReport request")
+		return c.Status(fiber.StatusCreated).JSON(report)
+	}
+}
+
+// GetReportedAdvertisements godoc
+// @Summary Жалобы по объявлениям
+// @Description Возвращает объявления с необработанными жалобами вместе с самими жалобами, сначала объявления с наибольшим числом жалоб.
+// @Description Только для модераторов и администраторов
+// @Security ApiKeyAuth
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param page query integer false "Номер страницы" default(1)
+// @Param limit query integer false "Лимит на странице" default(10)
+// @Success 200 {object} reports.ReportedAdvertisementPage
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'forbidden'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /moderation/reports [get]
+func GetReportedAdvertisements(c *fiber.Ctx) error {
+	filter := advertisements.NewDefaultFilter()
+	if err := c.QueryParser(&filter); err != nil {
+		logger.L.Error("[GetReportedAdvertisements | parse query]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := advertisements.ValidatePaginationInAdvertisementFilter(&filter); err != nil {
+		logger.L.Error("[GetReportedAdvertisements | validate]:", "error", err)
+		switch {
+		case errors.Is(err, advertisements.ErrWrongPage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "номер страницы должен быть положительным"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "лимит на странице должен быть от 1 до 100"})
+		}
+	}
+
+	// запрос к БД
+	page, err := repository.GetReportedAdvertisements(context.Background(), &filter)
+	if err != nil {
+		logger.L.Error("[GetReportedAdvertisements | exec get reports]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetReportedAdvertisements]: success GetReportedAdvertisements request")
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+// DismissReports godoc
+// @Summary Отклонение жалоб
+// @Description Закрывает жалобы на объявление, не меняя его статус. Скрытое по жалобам объявление нужно одобрить или отклонить.
+// @Description Только для модераторов и администраторов
+// @Security ApiKeyAuth
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Success 204
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'forbidden'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 409 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /moderation/advertisements/{id}/reports/dismiss [post]
+func DismissReports(c *fiber.Ctx) error {
+	// получим идентификатор объявления из пути
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		logger.L.Error("[DismissReports | parse id]: failed parse id", "id", c.Params("id"))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+	}
+
+	// запрос к БД
+	if err := repository.DismissReports(context.Background(), id); err != nil {
+		logger.L.Error("[DismissReports | exec]:", "error", err)
+		switch {
+		case errors.Is(err, repository.ErrAdvertisementNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "объявление не найдено"})
+		case errors.Is(err, advertisements.ErrWrongStatusTransition):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "скрытое объявление нужно одобрить или отклонить"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	logger.L.Info("[DismissReports]: success DismissReports request")
+	return c.SendStatus(fiber.StatusNoContent)
+}
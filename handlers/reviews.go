@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/repository"
+	"github.com/vk_intern/internal/reviews"
+)
+
+// CreateReview godoc
+// @Summary Отзыв о продавце
+// @Description Оставляет оценку от 1 до 5 и комментарий продавцу объявления. Доступно покупателю,
+// @Description которому продавец ответил в переписке по этому объявлению, один отзыв на объявление
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор объявления"
+// @Param reviewData body reviews.CreateReviewRequest true "Оценка и комментарий"
+// @Success 201 {object} reviews.Review
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 403 {object} map[string]interface{} "'error': 'message'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 409 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements/{id}/reviews [post]
+func CreateReview(c *fiber.Ctx) error {
+	// получим идентификатор объявления из пути
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		logger.L.Error("[CreateReview | parse id]: failed parse id", "id", c.Params("id"))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор объявления"})
+	}
+
+	var req reviews.CreateReviewRequest
+
+	//парсим JSON в структуру запроса
+	if err := c.BodyParser(&req); err != nil {
+		logger.L.Error("[CreateReview | parse JSON]: failed parse req", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	// провалидируем данные
+	if err := reviews.ValidateReview(&req); err != nil {
+		logger.L.Error("[CreateReview | validate]:", "error", err)
+		switch {
+		case errors.Is(err, reviews.ErrWrongRating):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "оценка должна быть от 1 до 5"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "комментарий может содержать не более 1000 символов"})
+		}
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[CreateReview | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	review, err := repository.CreateReview(context.Background(), loginInterface.(string), id, &req)
+	if err != nil {
+		logger.L.Error("[CreateReview | exec create review]:", "error", err)
+		switch {
+		case errors.Is(err, repository.ErrAdvertisementNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "объявление не найдено"})
+		case errors.Is(err, repository.ErrReviewOwnAdvertisement):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "нельзя оставить отзыв о себе"})
+		case errors.Is(err, repository.ErrReviewNoConversation):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "оставить отзыв можно только после ответа продавца в переписке по объявлению"})
+		case errors.Is(err, repository.ErrReviewExists):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "вы уже оставили отзыв по этому объявлению"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	logger.L.Info("[CreateReview]: success CreateReview request")
+	return c.Status(fiber.StatusCreated).JSON(review)
+}
+
+// GetReviews godoc
+// @Summary Отзывы о продавце
+// @Description Возвращает отзывы о пользователе как о продавце, новые идут первыми, вместе со средней оценкой и количеством отзывов
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param login path string true "Логин продавца"
+// @Param page query integer false "Номер страницы" default(1)
+// @Param limit query integer false "Лимит на странице" default(10)
+// @Success 200 {object} reviews.ReviewPage
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /users/{login}/reviews [get]
+func GetReviews(c *fiber.Ctx) error {
+	filter := advertisements.NewDefaultFilter()
+	if err := c.QueryParser(&filter); err != nil {
+		logger.L.Error("[GetReviews | parse query]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := advertisements.ValidatePaginationInAdvertisementFilter(&filter); err != nil {
+		logger.L.Error("[GetReviews | validate]:", "error", err)
+		switch {
+		case errors.Is(err, advertisements.ErrWrongPage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "номер страницы должен быть положительным"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "лимит на странице должен быть от 1 до 100"})
+		}
+	}
+
+	// запрос к БД
+	page, err := repository.GetReviews(context.Background(), c.Params("login"), filter.Page, filter.Limit)
+	if err != nil {
+		logger.L.Error("[GetReviews | exec get reviews]:", "error", err)
+		if errors.Is(err, repository.ErrUserLoginWrong) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "пользователь не найден"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetReviews]: success GetReviews request")
+	return c.Status(fiber.StatusOK).JSON(page)
+}
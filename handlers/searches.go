@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/repository"
+	"github.com/vk_intern/internal/searches"
+)
+
+// CreateSavedSearch godoc
+// @Summary Сохранение поиска
+// @Description Сохраняет параметры поиска объявлений. Новые опубликованные объявления, подходящие под поиск,
+// @Description попадают в /me/saved-searches/matches. Пользователь может сохранить не более 20 поисков
+// @Security ApiKeyAuth
+// @Tags saved searches
+// @Accept json
+// @Produce json
+// @Param searchData body searches.CreateSavedSearchRequest true "Параметры поиска"
+// @Success 201 {object} searches.SavedSearch
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 409 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me/saved-searches [post]
+func CreateSavedSearch(c *fiber.Ctx) error {
+	var req searches.CreateSavedSearchRequest
+
+	//парсим JSON в структуру запроса
+	if err := c.BodyParser(&req); err != nil {
+		logger.L.Error("[CreateSavedSearch | parse JSON]: failed parse req", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	// провалидируем параметры поиска
+	filter, err := searches.ValidateSavedSearch(&req)
+	if err != nil {
+		logger.L.Error("[CreateSavedSearch | validate]:", "error", err)
+
+		switch {
+		case errors.Is(err, searches.ErrEmptyName):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "необходимо указать название поиска"})
+		case errors.Is(err, searches.ErrLongName):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "название поиска может содержать не более 100 символов"})
+		case errors.Is(err, searches.ErrEmptySearch):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "необходимо указать хотя бы одно условие поиска"})
+		case errors.Is(err, advertisements.ErrPriceLessZero):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может быть отрицательной"})
+		case errors.Is(err, advertisements.ErrBigPrice):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может превышать 100 000 000"})
+		case errors.Is(err, advertisements.ErrBigPricePrecision):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может содержать больше 2 знаков после запятой"})
+		case errors.Is(err, advertisements.ErrLongSearchQuery):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "поисковый запрос может содержать не более 200 символов"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	// проверим, что категория поиска существует
+	if filter.Category != "" {
+		exists, err := repository.CheckCategoryExists(context.Background(), filter.Category)
+		if err != nil {
+			logger.L.Error("[CreateSavedSearch | check category]:", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		if !exists {
+			logger.L.Error("[CreateSavedSearch | check category]: category not found", "category", filter.Category)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "категория не найдена"})
+		}
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[CreateSavedSearch | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	search, err := repository.CreateSavedSearch(context.Background(), loginInterface.(string), req.Name, filter)
+	if err != nil {
+		logger.L.Error("[CreateSavedSearch | exec create search]:", "error", err)
+		if errors.Is(err, searches.ErrTooManySavedSearches) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "можно сохранить не более 20 поисков"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[CreateSavedSearch]: success CreateSavedSearch request")
+	return c.Status(fiber.StatusCreated).JSON(search)
+}
+
+// GetSavedSearches godoc
+// @Summary Сохраненные поиски
+// @Description Возвращает все сохраненные поиски пользователя
+// @Security ApiKeyAuth
+// @Tags saved searches
+// @Accept json
+// @Produce json
+// @Success 200 {array} searches.SavedSearch
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me/saved-searches [get]
+func GetSavedSearches(c *fiber.Ctx) error {
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[GetSavedSearches | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	result, err := repository.GetSavedSearches(context.Background(), loginInterface.(string))
+	if err != nil {
+		logger.L.Error("[GetSavedSearches | exec get searches]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetSavedSearches]: success GetSavedSearches request")
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// GetSavedSearchMatches godoc
+// @Summary Новые объявления по сохраненным поискам
+// @Description Возвращает объявления, которые после публикации подошли под сохраненные поиски пользователя,
+// @Description новые совпадения идут первыми
+// @Security ApiKeyAuth
+// @Tags saved searches
+// @Accept json
+// @Produce json
+// @Param page query integer false "Номер страницы" default(1)
+// @Param limit query integer false "Лимит на странице" default(10)
+// @Success 200 {object} searches.MatchedAdvertisementPage
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me/saved-searches/matches [get]
+func GetSavedSearchMatches(c *fiber.Ctx) error {
+	filter := advertisements.NewDefaultFilter()
+	if err := c.QueryParser(&filter); err != nil {
+		logger.L.Error("[GetSavedSearchMatches | parse query]:", "error", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат данных"})
+	}
+
+	if err := advertisements.ValidatePaginationInAdvertisementFilter(&filter); err != nil {
+		logger.L.Error("[GetSavedSearchMatches | validate]:", "error", err)
+		switch {
+		case errors.Is(err, advertisements.ErrWrongPage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "номер страницы должен быть положительным"})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "лимит на странице должен быть от 1 до 100"})
+		}
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[GetSavedSearchMatches | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	page, err := repository.GetSavedSearchMatches(context.Background(), loginInterface.(string), filter.Page, filter.Limit)
+	if err != nil {
+		logger.L.Error("[GetSavedSearchMatches | exec get matches]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[GetSavedSearchMatches]: success GetSavedSearchMatches request")
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+// DeleteSavedSearch godoc
+// @Summary Удаление сохраненного поиска
+// @Description Удаляет сохраненный поиск вместе с найденными по нему объявлениями
+// @Security ApiKeyAuth
+// @Tags saved searches
+// @Accept json
+// @Produce json
+// @Param id path integer true "Идентификатор поиска"
+// @Success 204
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 401 {object} map[string]interface{} "'error': 'unauthorized'"
+// @Failure 404 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /me/saved-searches/{id} [delete]
+func DeleteSavedSearch(c *fiber.Ctx) error {
+	// получим идентификатор поиска из пути
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		logger.L.Error("[DeleteSavedSearch | parse id]: failed parse id", "id", c.Params("id"))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный идентификатор поиска"})
+	}
+
+	// получим логин из контекста
+	loginInterface := c.Locals("login")
+	if loginInterface == nil {
+		logger.L.Error("[DeleteSavedSearch | get login]: could not get login from token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+
+	// запрос к БД
+	if err := repository.DeleteSavedSearch(context.Background(), loginInterface.(string), id); err != nil {
+		logger.L.Error("[DeleteSavedSearch | exec delete search]:", "error", err)
+		if errors.Is(err, repository.ErrSavedSearchNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "поиск не найден"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	logger.L.Info("[DeleteSavedSearch]: success DeleteSavedSearch request")
+	return c.SendStatus(fiber.StatusNoContent)
+}
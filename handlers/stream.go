@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/feed"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/repository"
+)
+
+const (
+	// через сколько клиент переподключается после обрыва потока
+	streamRetry = 3 * time.Second
+	// как часто отправлять комментарий, чтобы прокси не закрывали простаивающее соединение
+	streamPingPeriod = 30 * time.Second
+	// по сколько пропущенных публикаций читать из БД при возобновлении потока
+	streamReplayPageSize = 500
+)
+
+// StreamAdvertisements godoc
+// @Summary Поток свежих объявлений
+// @Description Server-Sent Events: событие advertisement приходит сразу после публикации объявления, id события - номер публикации.
+// @Description Учитываются фильтры по цене и категории (вместе с подкатегориями). При переподключении с заголовком Last-Event-ID
+// @Description досылаются все пропущенные публикации, которые все еще опубликованы
+// @Tags advertisements
+// @Produce text/event-stream
+// @Param min_price query number false "Минимальная цена"
+// @Param max_price query number false "Максимальная цена"
+// @Param category query string false "Slug категории, включая подкатегории"
+// @Param Last-Event-ID header integer false "Номер последнего полученного события"
+// @Success 200 {object} advertisements.AdvertisementResponse
+// @Failure 400 {object} map[string]interface{} "'error': 'message'"
+// @Failure 500 {object}  map[string]interface{} "'error': 'message'"
+// @Router /advertisements/stream [get]
+func StreamAdvertisements(stream *feed.Feed) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		// получим параметры фильтрации из query
+		params := advertisements.NewDefaultFilter()
+		if err := c.QueryParser(&params); err != nil {
+			logger.L.Error("[StreamAdvertisements | parse query]: failed parse params", "error", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректные параметры фильтра"})
+		}
+
+		// провалидируем цены фильтрации
+		if err := advertisements.ValidatePricesInAdverisementFilter(&params); err != nil {
+			logger.L.Error("[StreamAdvertisements | validate]:", "error", err)
+
+			switch {
+			case errors.Is(err, advertisements.ErrPriceLessZero):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может быть отрицательной"})
+			case errors.Is(err, advertisements.ErrBigPrice):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может превышать 100 000 000"})
+			case errors.Is(err, advertisements.ErrBigPricePrecision):
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "цена не может содержать больше 2 знаков после запятой"})
+			default:
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+
+		filter := feed.Filter{MinPrice: params.MinPrice, MaxPrice: params.MaxPrice}
+
+		// категорию вместе с подкатегориями разворачиваем один раз при подключении
+		if params.Category != "" {
+			ids, err := repository.GetCategorySubtree(context.Background(), params.Category)
+			if err != nil {
+				logger.L.Error("[StreamAdvertisements | get category]:", "error", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+			if len(ids) == 0 {
+				logger.L.Error("[StreamAdvertisements | get category]: category not found", "category", params.Category)
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "категория не найдена"})
+			}
+
+			filter.Categories = map[int]struct{}{}
+			for _, id := range ids {
+				filter.Categories[id] = struct{}{}
+			}
+		}
+
+		// номер последнего полученного события при переподключении
+		var lastEventID int64
+		resume := c.Get("Last-Event-ID") != ""
+		if resume {
+			var err error
+			lastEventID, err = strconv.ParseInt(c.Get("Last-Event-ID"), 10, 64)
+			if err != nil || lastEventID < 0 {
+				logger.L.Error("[StreamAdvertisements | parse Last-Event-ID]: failed parse id", "id", c.Get("Last-Event-ID"))
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "некорректный Last-Event-ID"})
+			}
+		}
+
+		// подписываемся до чтения пропущенного, чтобы не потерять публикации между ними
+		sub := stream.Subscribe(filter)
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer sub.Close()
+
+			fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+
+			// пропущенное досылаем страницами, пока не дойдем до последней публикации
+			for resume {
+				pubs, err := repository.GetPublications(context.Background(), lastEventID, &params, streamReplayPageSize)
+				if err != nil {
+					logger.L.Error("[StreamAdvertisements | exec get publications]:", "error", err)
+					return
+				}
+				for _, pub := range pubs {
+					if err := writePublication(w, pub); err != nil {
+						return
+					}
+					lastEventID = pub.EventID
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+				resume = len(pubs) == streamReplayPageSize
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			ping := time.NewTicker(streamPingPeriod)
+			defer ping.Stop()
+
+			for {
+				select {
+				case pub, ok := <-sub.Events:
+					if !ok {
+						return
+					}
+					// публикация уже отправлена из пропущенных
+					if pub.EventID <= lastEventID {
+						continue
+					}
+					if err := writePublication(w, pub); err != nil {
+						return
+					}
+					lastEventID = pub.EventID
+				case <-ping.C:
+					fmt.Fprint(w, ": ping\n\n")
+				}
+
+				// ошибка отправки означает, что клиент отключился
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		})
+
+		logger.L.Info("[StreamAdvertisements]: success StreamAdvertisements request")
+		return nil
+	}
+}
+
+// writePublication записывает публикацию в формате события Server-Sent Events
+func writePublication(w *bufio.Writer, pub *advertisements.Publication) error {
+	data, err := json.Marshal(pub.Advertisement)
+	if err != nil {
+		return fmt.Errorf("[writePublication|marshal adv]: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: advertisement\ndata: %s\n\n", pub.EventID, data); err != nil {
+		return fmt.Errorf("[writePublication|write event]: %w", err)
+	}
+	return nil
+}
@@ -17,6 +17,16 @@ const (
 	maxLenDescription = 500
 	maxPrice          = 100000000
 	maxPricePrecision = 2
+	maxLenSearchQuery = 200
+	maxLimit          = 100
+	maxLenReason      = 500
+)
+
+// варианты сортировки списка объявлений
+const (
+	OrderByPrice     = "price"
+	OrderByCreatedAt = "created_at"
+	OrderByRelevance = "relevance"
 )
 
 var ImageFormats = []string{".jpg", ".jpeg", ".png", ".webp"}
@@ -32,6 +42,17 @@ var (
 	ErrBigPricePrecision = errors.New("price precision bigger than required")
 	ErrWrongImageFormat  = errors.New("wrong image format")
 	ErrWrongURL          = errors.New("wrong image URL")
+	ErrEmptyUpdate       = errors.New("no fields to update")
+	ErrWrongInitStatus   = errors.New("new advertisement can only be draft or active")
+	ErrWrongOrderBy      = errors.New("wrong order_by parameter")
+	ErrWrongOrder        = errors.New("wrong order parameter")
+	ErrLongSearchQuery   = errors.New("search query is longer than required")
+	ErrRelevanceNoQuery  = errors.New("relevance ordering requires search query")
+	ErrEmptyCategory     = errors.New("category is required")
+	ErrWrongPage         = errors.New("page must be positive")
+	ErrWrongLimit        = errors.New("limit is out of range")
+	ErrEmptyReason       = errors.New("rejection reason is required")
+	ErrLongReason        = errors.New("rejection reason is longer than required")
 )
 
 func ValidateAdvertisement(adv *CreateAdvertisementRequest) error {
@@ -47,8 +68,82 @@ func ValidateAdvertisement(adv *CreateAdvertisementRequest) error {
 		return fmt.Errorf("[ValidateAdvertisement|price] %w", err)
 	}
 
-	if err := validateImageURL(adv.ImageURL); err != nil {
-		return fmt.Errorf("[ValidateAdvertisement|url] %w", err)
+	images, err := normalizeImages(adv.ImageURL, adv.Images)
+	if err != nil {
+		return fmt.Errorf("[ValidateAdvertisement|images] %w", err)
+	}
+	if err := validateImages(images, adv.CoverIndex); err != nil {
+		return fmt.Errorf("[ValidateAdvertisement|images] %w", err)
+	}
+	adv.Images = images
+	adv.ImageURL = images[adv.CoverIndex]
+
+	if err := validateCategoryID(adv.CategoryID); err != nil {
+		return fmt.Errorf("[ValidateAdvertisement|category] %w", err)
+	}
+
+	// новое объявление можно сохранить как черновик или сразу опубликовать
+	if adv.Status == "" {
+		adv.Status = StatusActive
+	}
+	if adv.Status != StatusDraft && adv.Status != StatusActive {
+		return fmt.Errorf("[ValidateAdvertisement|status] %w", ErrWrongInitStatus)
+	}
+
+	return nil
+}
+
+// ValidateUpdateAdvertisement проверяет только переданные для изменения поля
+// по тем же правилам, что и при создании объявления
+func ValidateUpdateAdvertisement(adv *UpdateAdvertisementRequest) error {
+	if adv.Title == nil && adv.Description == nil && adv.ImageURL == nil && adv.Images == nil &&
+		adv.CoverIndex == nil && adv.Price == nil && adv.CategoryID == nil {
+		return fmt.Errorf("[ValidateUpdateAdvertisement] %w", ErrEmptyUpdate)
+	}
+
+	if adv.Title != nil {
+		if err := validateTitle(*adv.Title); err != nil {
+			return fmt.Errorf("[ValidateUpdateAdvertisement|title] %w", err)
+		}
+	}
+
+	if adv.Description != nil {
+		if err := vaildateDescription(*adv.Description); err != nil {
+			return fmt.Errorf("[ValidateUpdateAdvertisement|description] %w", err)
+		}
+	}
+
+	if adv.Price != nil {
+		if err := validatePrice(*adv.Price); err != nil {
+			return fmt.Errorf("[ValidateUpdateAdvertisement|price] %w", err)
+		}
+	}
+
+	// image_url заменяет всю галерею одной фотографией
+	if adv.ImageURL != nil {
+		if adv.Images != nil {
+			return fmt.Errorf("[ValidateUpdateAdvertisement|images] %w", ErrImagesAndURLBoth)
+		}
+		adv.Images = &[]string{*adv.ImageURL}
+		adv.ImageURL = nil
+	}
+
+	if adv.Images != nil {
+		if adv.CoverIndex == nil {
+			adv.CoverIndex = new(int)
+		}
+		if err := validateImages(*adv.Images, *adv.CoverIndex); err != nil {
+			return fmt.Errorf("[ValidateUpdateAdvertisement|images] %w", err)
+		}
+	} else if adv.CoverIndex != nil && *adv.CoverIndex < 0 {
+		// без новой галереи границы обложки проверяются по уже сохраненным фотографиям
+		return fmt.Errorf("[ValidateUpdateAdvertisement|cover] %w", ErrWrongCoverIndex)
+	}
+
+	if adv.CategoryID != nil {
+		if err := validateCategoryID(*adv.CategoryID); err != nil {
+			return fmt.Errorf("[ValidateUpdateAdvertisement|category] %w", err)
+		}
 	}
 
 	return nil
@@ -117,15 +212,104 @@ func validateImageURL(imURL string) error {
 	return fmt.Errorf("[validateImageURL]: %w", ErrWrongImageFormat)
 }
 
+func validateCategoryID(id int) error {
+	// существование категории проверяется внешним ключом в БД
+	if id <= 0 {
+		return fmt.Errorf("[validateCategoryID]: %w", ErrEmptyCategory)
+	}
+	return nil
+}
+
+// ValidateRejectReason проверяет причину отклонения объявления, которую увидит его автор
+func ValidateRejectReason(req *RejectRequest) error {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return fmt.Errorf("[ValidateRejectReason]: %w", ErrEmptyReason)
+	}
+	if utf8.RuneCountInString(req.Reason) > maxLenReason {
+		return fmt.Errorf("[ValidateRejectReason]: %w", ErrLongReason)
+	}
+	return nil
+}
+
 func NewDefaultFilter() AdvertisementFilter {
 	return AdvertisementFilter{
 		Page:     1,
 		Limit:    10,
-		OrderBy:  "created_at",
+		OrderBy:  OrderByCreatedAt,
 		Order:    "DESC",
 		MinPrice: 0,
 		MaxPrice: 100000000,
+		Status:   StatusActive,
+	}
+}
+
+func ValidateStatusInAdvertisementFilter(filter *AdvertisementFilter) error {
+	if err := ValidateStatus(filter.Status); err != nil {
+		return fmt.Errorf("[ValidateStatusInAdvertisementFilter|status] %w", err)
+	}
+	return nil
+}
+
+func ValidateOrderInAdvertisementFilter(filter *AdvertisementFilter) error {
+	switch filter.OrderBy {
+	case OrderByPrice, OrderByCreatedAt, OrderByRelevance:
+	default:
+		return fmt.Errorf("[ValidateOrderInAdvertisementFilter|order_by]: %w", ErrWrongOrderBy)
+	}
+
+	// направление сортировки подставляется в запрос, поэтому допускаем только ASC и DESC
+	filter.Order = strings.ToUpper(filter.Order)
+	if filter.Order != "ASC" && filter.Order != "DESC" {
+		return fmt.Errorf("[ValidateOrderInAdvertisementFilter|order]: %w", ErrWrongOrder)
+	}
+	return nil
+}
+
+func ValidateSearchInAdvertisementFilter(filter *AdvertisementFilter) error {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if utf8.RuneCountInString(filter.Query) > maxLenSearchQuery {
+		return fmt.Errorf("[ValidateSearchInAdvertisementFilter|q]: %w", ErrLongSearchQuery)
 	}
+
+	// сортировать по релевантности можно только результаты поиска
+	if filter.OrderBy == OrderByRelevance && filter.Query == "" {
+		return fmt.Errorf("[ValidateSearchInAdvertisementFilter|order_by]: %w", ErrRelevanceNoQuery)
+	}
+	return nil
+}
+
+func ValidatePaginationInAdvertisementFilter(filter *AdvertisementFilter) error {
+	if filter.Page < 1 {
+		return fmt.Errorf("[ValidatePaginationInAdvertisementFilter|page]: %w", ErrWrongPage)
+	}
+	if filter.Limit < 1 || filter.Limit > maxLimit {
+		return fmt.Errorf("[ValidatePaginationInAdvertisementFilter|limit]: %w", ErrWrongLimit)
+	}
+
+	if !filter.UseCursor {
+		return nil
+	}
+
+	// по курсору можно листать только ленту с однозначным порядком по полю объявления
+	if filter.OrderBy != OrderByCreatedAt && filter.OrderBy != OrderByPrice {
+		return fmt.Errorf("[ValidatePaginationInAdvertisementFilter|order_by]: %w", ErrCursorNotSupported)
+	}
+
+	// пустой курсор означает первую страницу
+	if filter.Cursor == "" {
+		return nil
+	}
+
+	cursor, err := DecodeCursor(filter.Cursor)
+	if err != nil {
+		return fmt.Errorf("[ValidatePaginationInAdvertisementFilter|cursor] %w", err)
+	}
+	if cursor.OrderBy != filter.OrderBy || cursor.Order != filter.Order {
+		return fmt.Errorf("[ValidatePaginationInAdvertisementFilter|cursor]: %w", ErrCursorParamsMismatch)
+	}
+	filter.After = cursor
+	return nil
 }
 
 func ValidatePricesInAdverisementFilter(filer *AdvertisementFilter) error {
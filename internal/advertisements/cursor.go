@@ -0,0 +1,58 @@
+package advertisements
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrWrongCursor          = errors.New("wrong pagination cursor")
+	ErrCursorNotSupported   = errors.New("cursor pagination is not supported for this ordering")
+	ErrCursorParamsMismatch = errors.New("pagination cursor does not match ordering parameters")
+)
+
+// Cursor позиция в ленте объявлений для постраничной выдачи по ключу (keyset):
+// значение поля сортировки и id последнего показанного объявления
+type Cursor struct {
+	OrderBy   string    `json:"o"`
+	Order     string    `json:"d"`
+	CreatedAt time.Time `json:"c,omitempty"`
+	Price     float64   `json:"p,omitempty"`
+	ID        int       `json:"i"`
+}
+
+// NewCursor создает курсор, указывающий на объявление adv в ленте с сортировкой filter
+func NewCursor(filter *AdvertisementFilter, adv *AdvertisementResponse) *Cursor {
+	return &Cursor{
+		OrderBy:   filter.OrderBy,
+		Order:     filter.Order,
+		CreatedAt: adv.CreatedAt,
+		Price:     adv.Price,
+		ID:        adv.ID,
+	}
+}
+
+// Encode возвращает непрозрачную для клиента строку курсора
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("[DecodeCursor|decode base64]: %w", ErrWrongCursor)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("[DecodeCursor|unmarshal]: %w", ErrWrongCursor)
+	}
+	if c.ID <= 0 {
+		return nil, fmt.Errorf("[DecodeCursor|check id]: %w", ErrWrongCursor)
+	}
+	return &c, nil
+}
@@ -0,0 +1,141 @@
+package advertisements
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+
+	_ "golang.org/x/image/webp"
+)
+
+// MaxImages максимальное количество фотографий в одном объявлении
+const MaxImages = 10
+
+var (
+	ErrNoImages         = errors.New("at least one image is required")
+	ErrTooManyImages    = errors.New("too many images")
+	ErrWrongCoverIndex  = errors.New("cover index is out of range")
+	ErrImagesAndURLBoth = errors.New("image_url and images can not be used together")
+	ErrUnknownImageData = errors.New("image content does not match any supported format")
+	ErrImageTooLarge    = errors.New("image resolution exceeds the limit")
+)
+
+// ImageSniffLen количество первых байт файла, достаточное для определения формата изображения
+const ImageSniffLen = 12
+
+// сигнатуры (magic bytes) поддерживаемых форматов изображений
+var (
+	jpegSignature = []byte{0xFF, 0xD8, 0xFF}
+	pngSignature  = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+	riffSignature = []byte("RIFF")
+	webpSignature = []byte("WEBP")
+)
+
+// DetectImageFormat определяет формат изображения по содержимому, а не по расширению,
+// и возвращает расширение из ImageFormats, под которым файл нужно сохранить
+func DetectImageFormat(head []byte) (string, error) {
+	var ext string
+	switch {
+	case bytes.HasPrefix(head, jpegSignature):
+		ext = ".jpg"
+	case bytes.HasPrefix(head, pngSignature):
+		ext = ".png"
+	case len(head) >= 12 && bytes.Equal(head[:4], riffSignature) && bytes.Equal(head[8:12], webpSignature):
+		ext = ".webp"
+	}
+
+	for _, e := range ImageFormats {
+		if ext != "" && e == ext {
+			return ext, nil
+		}
+	}
+	return "", fmt.Errorf("[DetectImageFormat]: %w", ErrUnknownImageData)
+}
+
+// CheckImageResolution читает только заголовок изображения и проверяет, что в нем не больше
+// maxPixels пикселей. Небольшой по размеру файл может объявлять огромное разрешение,
+// и полное декодирование такого файла займет всю память
+func CheckImageResolution(r io.Reader, maxPixels int) error {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return fmt.Errorf("[CheckImageResolution|decode config]: %w", err)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > int64(maxPixels) {
+		return fmt.Errorf("[CheckImageResolution]: %dx%d: %w", cfg.Width, cfg.Height, ErrImageTooLarge)
+	}
+	return nil
+}
+
+// ImageContentType возвращает MIME-тип для расширения из ImageFormats
+func ImageContentType(ext string) string {
+	switch ext {
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".webp":
+		return "image/webp"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// normalizeImages приводит устаревшее поле image_url к списку images
+func normalizeImages(imageURL string, images []string) ([]string, error) {
+	if imageURL == "" {
+		return images, nil
+	}
+	if len(images) > 0 {
+		return nil, fmt.Errorf("[normalizeImages]: %w", ErrImagesAndURLBoth)
+	}
+	return []string{imageURL}, nil
+}
+
+func validateImages(images []string, coverIndex int) error {
+	if len(images) == 0 {
+		return fmt.Errorf("[validateImages]: %w", ErrNoImages)
+	}
+	if len(images) > MaxImages {
+		return fmt.Errorf("[validateImages]: %w", ErrTooManyImages)
+	}
+
+	for _, im := range images {
+		if err := validateImageURL(im); err != nil {
+			return fmt.Errorf("[validateImages] %w", err)
+		}
+	}
+
+	if err := validateCoverIndex(coverIndex, len(images)); err != nil {
+		return fmt.Errorf("[validateImages] %w", err)
+	}
+	return nil
+}
+
+func validateCoverIndex(coverIndex, count int) error {
+	if coverIndex < 0 || coverIndex >= count {
+		return fmt.Errorf("[validateCoverIndex]: %w", ErrWrongCoverIndex)
+	}
+	return nil
+}
+
+// CoverIndexInRange проверяет, что обложка указывает на одну из count уже загруженных фотографий
+func CoverIndexInRange(coverIndex, count int) error {
+	return validateCoverIndex(coverIndex, count)
+}
+
+// BuildImages собирает галерею объявления в заданном порядке с отмеченной обложкой
+func BuildImages(urls []string, coverIndex int) []AdvertisementImage {
+	images := make([]AdvertisementImage, 0, len(urls))
+	for i, u := range urls {
+		images = append(images, AdvertisementImage{
+			URL:      u,
+			Position: i,
+			IsCover:  i == coverIndex,
+		})
+	}
+	return images
+}
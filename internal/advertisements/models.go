@@ -5,41 +5,151 @@ import "time"
 // Advertisement полная модель объявления
 // @Description Модель описывает объявление для возврата при его создании
 type Advertisement struct {
+	ID          int       `json:"id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	ImageURL    string    `json:"image_url"`
 	Price       float64   `json:"price"`
 	UserLogin   string    `json:"userlogin"`
+	Status      string    `json:"status" example:"active"`
+	CategoryID  int       `json:"category_id,omitempty"`
 	CreatedAt   time.Time `json:"created_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+
+	// причина отклонения модератором, видна только автору объявления
+	ModerationReason string `json:"moderation_reason,omitempty"`
+
+	Images []AdvertisementImage `json:"images,omitempty"`
 }
 
 // Advertisement модель запроса объявления
 // @Description Модель описывает запрос на создание объявления
 type CreateAdvertisementRequest struct {
-	Title       string  `json:"title" validate:"required"`
-	Description string  `json:"description" validate:"required"`
-	ImageURL    string  `json:"image_url" validate:"required"`
-	Price       float64 `json:"price" validate:"required"`
+	Title       string   `json:"title" validate:"required"`
+	Description string   `json:"description" validate:"required"`
+	ImageURL    string   `json:"image_url,omitempty"`
+	Images      []string `json:"images" validate:"required"`
+	CoverIndex  int      `json:"cover_index,omitempty"`
+	Price       float64  `json:"price" validate:"required"`
+	Status      string   `json:"status,omitempty" example:"active"`
+	CategoryID  int      `json:"category_id" validate:"required"`
 	UserLogin   string
 }
 
+// UpdateAdvertisementRequest модель запроса на изменение объявления
+// @Description Модель описывает запрос на изменение объявления, передаются только изменяемые поля
+type UpdateAdvertisementRequest struct {
+	Title       *string   `json:"title,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	ImageURL    *string   `json:"image_url,omitempty"`
+	Images      *[]string `json:"images,omitempty"`
+	CoverIndex  *int      `json:"cover_index,omitempty"`
+	Price       *float64  `json:"price,omitempty"`
+	CategoryID  *int      `json:"category_id,omitempty"`
+}
+
 // Advertisement модель объявления при получении
 // @Description Модель описывает ответ на получение объявления
 type AdvertisementResponse struct {
+	ID          int       `json:"id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	ImageURL    string    `json:"image_url"`
 	Price       float64   `json:"price"`
 	UserLogin   string    `json:"userlogin"`
+	Status      string    `json:"status" example:"active"`
+	CategoryID  int       `json:"category_id,omitempty"`
 	CreatedAt   time.Time `json:"created_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
 	IsMine      bool      `json:"ismine,omitempty"`
+	IsFavorite  bool      `json:"is_favorite,omitempty"`
+
+	// средняя оценка продавца по отзывам покупателей, пустая, пока отзывов нет
+	SellerRating *float64 `json:"seller_rating,omitempty" example:"4.67"`
+
+	// причина отклонения модератором, видна только автору объявления
+	ModerationReason string `json:"moderation_reason,omitempty"`
+
+	// статистика объявления видна только его автору в списке своих объявлений
+	Stats *AdvertisementStats `json:"stats,omitempty"`
+
+	// миниатюры обложки по размерам (small, medium, large), только для фотографий, загруженных в сервис
+	Thumbnails map[string]string `json:"thumbnails,omitempty"`
+
+	// галерея заполняется только при получении одного объявления, в списках есть только обложка image_url
+	Images []AdvertisementImage `json:"images,omitempty"`
+}
+
+// AdvertisementStats статистика объявления
+// @Description Модель описывает статистику объявления, которую видит только его автор:
+// @Description просмотры карточки другими пользователями (не больше одного в день от пользователя или IP адреса), добавления в избранное и переписки с покупателями
+type AdvertisementStats struct {
+	Views         int `json:"views"`
+	Favorites     int `json:"favorites"`
+	Conversations int `json:"conversations"`
+}
+
+// AdvertisementImage фотография объявления
+// @Description Модель описывает фотографию из галереи объявления
+type AdvertisementImage struct {
+	URL      string `json:"url"`
+	Position int    `json:"position"`
+	IsCover  bool   `json:"is_cover"`
+}
+
+// AdvertisementPage страница ленты объявлений
+// @Description Модель описывает страницу объявлений вместе с общим количеством и признаком следующей страницы.
+// @Description page заполняется при постраничной выдаче, next_cursor - при выдаче по курсору
+type AdvertisementPage struct {
+	Items      []*AdvertisementResponse `json:"items"`
+	Total      int                      `json:"total"`
+	Page       int                      `json:"page,omitempty"`
+	Limit      int                      `json:"limit"`
+	HasNext    bool                     `json:"has_next"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+}
+
+// Publication событие публикации объявления в потоке свежих объявлений,
+// EventID растет с каждой публикацией и служит для возобновления потока
+type Publication struct {
+	EventID       int64
+	Advertisement *AdvertisementResponse
+}
+
+// ChangeStatusRequest модель запроса на смену статуса объявления
+// @Description Модель описывает новый статус объявления: draft, active, reserved, sold, archived.
+// @Description При включенной премодерации публикация (active) отправляет объявление на проверку (pending)
+type ChangeStatusRequest struct {
+	Status string `json:"status" validate:"required" example:"sold"`
+}
+
+// RejectRequest модель запроса на отклонение объявления
+// @Description Модель описывает причину отклонения объявления, которую увидит его автор
+type RejectRequest struct {
+	Reason string `json:"reason" validate:"required" example:"фотографии не соответствуют описанию"`
 }
 
 type AdvertisementFilter struct {
 	Page     int     `query:"page"`
 	Limit    int     `query:"limit"`
-	OrderBy  string  `query:"order_by" validate:"oneof=price created_at"`
+	OrderBy  string  `query:"order_by" validate:"oneof=price created_at relevance"`
 	Order    string  `query:"order" validate:"oneof=ASC DESC"`
 	MinPrice float64 `query:"min_price"`
 	MaxPrice float64 `query:"max_price"`
+	Status   string  `query:"status"`
+	Query    string  `query:"q"`
+	Category string  `query:"category"`
+	Cursor   string  `query:"cursor"`
+
+	// UseCursor включает постраничную выдачу по курсору вместо page/limit,
+	// After - разобранный курсор, после которого начинается страница (nil для первой страницы)
+	UseCursor bool    `query:"-"`
+	After     *Cursor `query:"-"`
+
+	// WithTotal включает подсчет общего количества объявлений по фильтру
+	WithTotal bool `query:"-"`
+}
+
+// ImageUploadResponse модель ответа на загрузку фотографии
+// @Description Модель описывает адрес загруженной фотографии для передачи в images объявления
+type ImageUploadResponse struct {
+	URL string `json:"url" example:"/images/3f2a9c0d1b7e4e5f8a6b2c1d0e9f8a7b.jpg"`
 }
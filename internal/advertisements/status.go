@@ -0,0 +1,109 @@
+package advertisements
+
+import (
+	"errors"
+	"fmt"
+)
+
+// статусы жизненного цикла объявления
+const (
+	StatusDraft    = "draft"
+	StatusPending  = "pending"
+	StatusActive   = "active"
+	StatusRejected = "rejected"
+	StatusHidden   = "hidden"
+	StatusReserved = "reserved"
+	StatusSold     = "sold"
+	StatusArchived = "archived"
+)
+
+var Statuses = []string{StatusDraft, StatusPending, StatusActive, StatusRejected, StatusHidden, StatusReserved, StatusSold, StatusArchived}
+
+// PublicStatuses статусы, в которых объявление видно всем пользователям
+var PublicStatuses = []string{StatusActive, StatusReserved, StatusSold}
+
+// допустимые переходы между статусами: из ключа можно перейти в любой статус из значения.
+// В pending, rejected и hidden объявление переводит только модерация
+var statusTransitions = map[string][]string{
+	StatusDraft:    {StatusActive, StatusArchived},
+	StatusPending:  {StatusDraft, StatusArchived},
+	StatusRejected: {StatusDraft, StatusActive, StatusArchived},
+	StatusHidden:   {StatusArchived},
+	StatusActive:   {StatusDraft, StatusReserved, StatusSold, StatusArchived},
+	StatusReserved: {StatusActive, StatusSold, StatusArchived},
+	StatusSold:     {StatusArchived},
+	StatusArchived: {StatusDraft, StatusActive},
+}
+
+var (
+	ErrUnknownStatus         = errors.New("unknown advertisement status")
+	ErrWrongStatusTransition = errors.New("advertisement status transition is not allowed")
+)
+
+func ValidateStatus(status string) error {
+	for _, s := range Statuses {
+		if s == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("[ValidateStatus]: %w", ErrUnknownStatus)
+}
+
+func ValidateStatusTransition(from, to string) error {
+	if err := ValidateStatus(to); err != nil {
+		return fmt.Errorf("[ValidateStatusTransition] %w", err)
+	}
+
+	for _, s := range statusTransitions[from] {
+		if s == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("[ValidateStatusTransition]: %s -> %s %w", from, to, ErrWrongStatusTransition)
+}
+
+// решения модератора: объявление из очереди или скрытое по жалобам можно одобрить или отклонить
+var moderationTransitions = map[string][]string{
+	StatusPending: {StatusActive, StatusRejected},
+	StatusHidden:  {StatusActive, StatusRejected},
+}
+
+func ValidateModerationTransition(from, to string) error {
+	for _, s := range moderationTransitions[from] {
+		if s == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("[ValidateModerationTransition]: %s -> %s %w", from, to, ErrWrongStatusTransition)
+}
+
+// ApplyPremoderation при включенной премодерации отправляет объявление на проверку вместо публикации.
+// Объявления, уже прошедшие проверку (например, снятые с брони), возвращаются в ленту сразу
+func ApplyPremoderation(from, to string) string {
+	if to == StatusActive && !IsPublicStatus(from) {
+		return StatusPending
+	}
+	return to
+}
+
+// ApplyEditPremoderation возвращает статус объявления после изменения автором его содержимого.
+// Применяется при включенной премодерации: активное объявление с новым текстом или фотографиями
+// модератор еще не видел, поэтому оно снова отправляется на проверку. Забронированные и проданные
+// объявления статус сохраняют, иначе после одобрения они снова оказались бы в продаже
+func ApplyEditPremoderation(status string) string {
+	if status == StatusActive {
+		return StatusPending
+	}
+	return status
+}
+
+// IsPublicStatus сообщает, видно ли объявление в этом статусе всем пользователям,
+// объявления в остальных статусах видит только их автор
+func IsPublicStatus(status string) bool {
+	for _, s := range PublicStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package advertisements
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateModerationTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{name: "approve pending", from: StatusPending, to: StatusActive},
+		{name: "reject pending", from: StatusPending, to: StatusRejected},
+		{name: "approve hidden", from: StatusHidden, to: StatusActive},
+		{name: "reject hidden", from: StatusHidden, to: StatusRejected},
+		{name: "pending to draft", from: StatusPending, to: StatusDraft, wantErr: true},
+		{name: "reject active", from: StatusActive, to: StatusRejected, wantErr: true},
+		{name: "reject reserved", from: StatusReserved, to: StatusRejected, wantErr: true},
+		{name: "approve rejected", from: StatusRejected, to: StatusActive, wantErr: true},
+		{name: "approve sold", from: StatusSold, to: StatusActive, wantErr: true},
+		{name: "unknown status", from: "unknown", to: StatusActive, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateModerationTransition(tt.from, tt.to)
+			if tt.wantErr {
+				if !errors.Is(err, ErrWrongStatusTransition) {
+					t.Fatalf("ValidateModerationTransition(%q, %q) error = %v, want %v", tt.from, tt.to, err, ErrWrongStatusTransition)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateModerationTransition(%q, %q) unexpected error: %v", tt.from, tt.to, err)
+			}
+		})
+	}
+}
+
+func TestApplyPremoderation(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{name: "publish new", from: "", to: StatusActive, want: StatusPending},
+		{name: "publish draft", from: StatusDraft, to: StatusActive, want: StatusPending},
+		{name: "publish archived", from: StatusArchived, to: StatusActive, want: StatusPending},
+		{name: "publish rejected", from: StatusRejected, to: StatusActive, want: StatusPending},
+		{name: "unreserve", from: StatusReserved, to: StatusActive, want: StatusActive},
+		{name: "reserve", from: StatusActive, to: StatusReserved, want: StatusReserved},
+		{name: "sell", from: StatusActive, to: StatusSold, want: StatusSold},
+		{name: "archive", from: StatusActive, to: StatusArchived, want: StatusArchived},
+		{name: "save draft", from: "", to: StatusDraft, want: StatusDraft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyPremoderation(tt.from, tt.to); got != tt.want {
+				t.Fatalf("ApplyPremoderation(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEditPremoderation(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{status: StatusActive, want: StatusPending},
+		{status: StatusReserved, want: StatusReserved},
+		{status: StatusSold, want: StatusSold},
+		{status: StatusDraft, want: StatusDraft},
+		{status: StatusPending, want: StatusPending},
+		{status: StatusRejected, want: StatusRejected},
+		{status: StatusHidden, want: StatusHidden},
+		{status: StatusArchived, want: StatusArchived},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := ApplyEditPremoderation(tt.status); got != tt.want {
+				t.Fatalf("ApplyEditPremoderation(%q) = %q, want %q", tt.status, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package advertisements
+
+import (
+	"path"
+	"strings"
+)
+
+// ImagesURLPrefix префикс адресов, по которым отдаются загруженные в сервис фотографии
+const ImagesURLPrefix = "/images/"
+
+// ThumbnailSize размер миниатюры: наибольшая сторона в пикселях
+type ThumbnailSize struct {
+	Name string
+	Max  int
+}
+
+// ThumbnailSizes фиксированные размеры миниатюр, генерируемых для загруженных фотографий
+var ThumbnailSizes = []ThumbnailSize{
+	{Name: "small", Max: 160},
+	{Name: "medium", Max: 480},
+	{Name: "large", Max: 1024},
+}
+
+// ThumbnailName возвращает имя файла миниатюры размера size для фотографии name
+func ThumbnailName(name, size string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "_" + size + ext
+}
+
+// ThumbnailOriginal по имени файла миниатюры возвращает имя исходной фотографии
+func ThumbnailOriginal(name string) (string, bool) {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for _, s := range ThumbnailSizes {
+		if orig, ok := strings.CutSuffix(base, "_"+s.Name); ok && orig != "" {
+			return orig + ext, true
+		}
+	}
+	return "", false
+}
+
+// ThumbnailURLs возвращает адреса миниатюр по размерам для фотографии, загруженной в сервис.
+// Для внешних адресов миниатюр нет и возвращается nil
+func ThumbnailURLs(imageURL string) map[string]string {
+	name, ok := strings.CutPrefix(imageURL, ImagesURLPrefix)
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return nil
+	}
+
+	urls := make(map[string]string, len(ThumbnailSizes))
+	for _, s := range ThumbnailSizes {
+		urls[s.Name] = ImagesURLPrefix + ThumbnailName(name, s.Name)
+	}
+	return urls
+}
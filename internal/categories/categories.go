@@ -0,0 +1,27 @@
+package categories
+
+// BuildTree собирает плоский список категорий в дерево,
+// порядок категорий на каждом уровне сохраняется как во входном списке
+func BuildTree(flat []*Category) []*Category {
+	byID := make(map[int]*Category, len(flat))
+	for _, c := range flat {
+		byID[c.ID] = c
+	}
+
+	roots := []*Category{}
+	for _, c := range flat {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+
+		parent, ok := byID[*c.ParentID]
+		if !ok {
+			// родитель не найден, показываем категорию на верхнем уровне
+			roots = append(roots, c)
+			continue
+		}
+		parent.Children = append(parent.Children, c)
+	}
+	return roots
+}
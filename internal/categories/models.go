@@ -0,0 +1,11 @@
+package categories
+
+// Category модель категории объявлений
+// @Description Модель описывает категорию с вложенными подкатегориями
+type Category struct {
+	ID       int         `json:"id"`
+	ParentID *int        `json:"parent_id,omitempty"`
+	Slug     string      `json:"slug" example:"phones"`
+	Name     string      `json:"name" example:"Телефоны"`
+	Children []*Category `json:"children,omitempty"`
+}
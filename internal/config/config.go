@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/joho/godotenv"
@@ -10,6 +11,11 @@ import (
 type Config struct {
 	Server struct {
 		Port string `env:"SERVER_PORT" envDefault:":3000"`
+
+		// заголовок обратного прокси с IP адресом клиента (например, X-Forwarded-For) и адреса прокси,
+		// которым доверяется этот заголовок. Без прокси заголовок не задается
+		ProxyHeader    string   `env:"SERVER_PROXY_HEADER"`
+		TrustedProxies []string `env:"SERVER_TRUSTED_PROXIES" envSeparator:","`
 	}
 
 	Storage struct {
@@ -21,7 +27,75 @@ type Config struct {
 	}
 
 	JWT struct {
-		JWTsecret string `env:"JWT_SECRET,required"`
+		JWTsecret  string        `env:"JWT_SECRET"`                        // секрет HS256, без kid в заголовке токена
+		AccessTTL  time.Duration `env:"JWT_ACCESS_TTL" envDefault:"15m"`   // время действия access токена
+		RefreshTTL time.Duration `env:"JWT_REFRESH_TTL" envDefault:"720h"` // время действия refresh токена
+
+		// сколько экземпляр сервиса доверяет закешированному результату проверки отзыва токена
+		RevocationCacheTTL time.Duration `env:"JWT_REVOCATION_CACHE_TTL" envDefault:"10s"`
+
+		Algorithm    string `env:"JWT_ALGORITHM" envDefault:"HS256"` // HS256, RS256 или EdDSA
+		SigningKeyID string `env:"JWT_SIGNING_KEY_ID"`               // kid ключа, которым подписываются новые токены
+
+		// ключи в виде kid:путь к PEM файлу. Закрытые ключи подписывают и проверяют токены,
+		// открытые только проверяют (ключи, выведенные из ротации, пока не истекли их токены)
+		PrivateKeys []string `env:"JWT_PRIVATE_KEYS" envSeparator:","`
+		PublicKeys  []string `env:"JWT_PUBLIC_KEYS" envSeparator:","`
+
+		// принимать токены без kid, подписанные JWT_SECRET, при подписи RS256 или EdDSA. Включается
+		// только на время перехода с HS256, пока не истекут выданные секретом токены
+		HS256Fallback bool `env:"JWT_HS256_FALLBACK" envDefault:"false"`
+	}
+
+	Admin struct {
+		// логины, которым при запуске выдается роль администратора, если администраторов еще нет.
+		// Пользователи должны быть уже зарегистрированы, дальше роли раздаются через API
+		Logins []string `env:"ADMIN_LOGINS" envSeparator:","`
+	}
+
+	Moderation struct {
+		// премодерация: новые объявления попадают в ленту только после одобрения модератором
+		Premoderation bool `env:"MODERATION_PREMODERATION" envDefault:"false"`
+	}
+
+	Reports struct {
+		// сколько жалоб от разных пользователей скрывает объявление до решения модератора, 0 отключает скрытие
+		HideThreshold int `env:"REPORTS_HIDE_THRESHOLD" envDefault:"5"`
+	}
+
+	Searches struct {
+		// обработчики сверки опубликованных объявлений с сохраненными поисками
+		MatchWorkers int `env:"SEARCHES_MATCH_WORKERS" envDefault:"1"`
+		MatchQueue   int `env:"SEARCHES_MATCH_QUEUE" envDefault:"1000"`
+	}
+
+	Stream struct {
+		// сколько публикаций копится для медленного подписчика потока свежих объявлений, отставший
+		// подписчик отключается и досылает пропущенное после переподключения
+		SubscriberBuffer int `env:"STREAM_SUBSCRIBER_BUFFER" envDefault:"16"`
+	}
+
+	Realtime struct {
+		// сколько событий копится для медленного WebSocket подключения, дальше события для него пропускаются
+		SubscriberBuffer int `env:"REALTIME_SUBSCRIBER_BUFFER" envDefault:"64"`
+	}
+
+	Views struct {
+		// обработчики записи просмотров объявлений и сколько дней хранить просмотры по зрителям,
+		// более старые просмотры сворачиваются в счетчики объявлений
+		Workers       int `env:"VIEWS_WORKERS" envDefault:"1"`
+		Queue         int `env:"VIEWS_QUEUE" envDefault:"1000"`
+		RetentionDays int `env:"VIEWS_RETENTION_DAYS" envDefault:"30"`
+	}
+
+	Images struct {
+		Dir     string `env:"IMAGES_DIR" envDefault:"./uploads"`
+		MaxSize int    `env:"IMAGES_MAX_SIZE" envDefault:"5242880"` // максимальный размер фотографии в байтах
+		// максимальное разрешение фотографии в пикселях (ширина * высота)
+		MaxPixels int `env:"IMAGES_MAX_PIXELS" envDefault:"40000000"`
+
+		ThumbnailWorkers int `env:"IMAGES_THUMBNAIL_WORKERS" envDefault:"2"`
+		ThumbnailQueue   int `env:"IMAGES_THUMBNAIL_QUEUE" envDefault:"100"`
 	}
 }
 
@@ -0,0 +1,188 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/repository"
+)
+
+const (
+	// как часто проверять новые публикации без сигнала, например сделанные другим экземпляром сервиса
+	pollPeriod = 5 * time.Second
+	// сколько публикаций читать из БД за раз
+	pageSize = 500
+)
+
+// Feed записывает публикации объявлений и в фоне раздает их подписчикам потока свежих объявлений
+type Feed struct {
+	wake   chan struct{}
+	buffer int
+	lastID int64 // номер последней разосланной публикации, меняет только обработчик раздачи
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// Filter условия подписки на поток: диапазон цен и категории, nil Categories - любая категория
+type Filter struct {
+	MinPrice   float64
+	MaxPrice   float64
+	Categories map[int]struct{}
+}
+
+// Match сообщает, подходит ли объявление под условия подписки
+func (f *Filter) Match(adv *advertisements.AdvertisementResponse) bool {
+	if adv.Price < f.MinPrice || adv.Price > f.MaxPrice {
+		return false
+	}
+	if f.Categories != nil {
+		if _, ok := f.Categories[adv.CategoryID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Subscription подписка на поток, после Close или отставания подписчика канал Events закрывается
+type Subscription struct {
+	Events <-chan *advertisements.Publication
+
+	events chan *advertisements.Publication
+	filter Filter
+	feed   *Feed
+}
+
+func NewFeed(buffer int) *Feed {
+	return &Feed{
+		wake:   make(chan struct{}, 1),
+		buffer: buffer,
+		subs:   map[*Subscription]struct{}{},
+	}
+}
+
+// Run запускает раздачу публикаций подписчикам начиная с последней записанной, раздача завершается
+// при отмене ctx. Раздает один обработчик, чтобы подписчики получали публикации в порядке их номеров
+func (f *Feed) Run(ctx context.Context) error {
+	lastID, err := repository.GetLastPublicationID(ctx)
+	if err != nil {
+		return fmt.Errorf("[Feed.Run] %w", err)
+	}
+	f.lastID = lastID
+
+	go func() {
+		ticker := time.NewTicker(pollPeriod)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-f.wake:
+			case <-ticker.C:
+			}
+
+			if err := f.broadcastNew(ctx); err != nil {
+				logger.L.Error("[Feed.Run | broadcast]:", "error", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Publish записывает публикацию объявления id, если это его первая публикация, и будит раздачу.
+// Запись синхронная, поэтому публикация не теряется, даже если раздача отстает: подписчики
+// получат ее при следующем чтении из БД, а переподключившиеся клиенты - по Last-Event-ID
+func (f *Feed) Publish(ctx context.Context, id int) error {
+	created, err := repository.CreatePublication(ctx, id)
+	if err != nil {
+		return fmt.Errorf("[Feed.Publish] %w", err)
+	}
+	if !created {
+		return nil
+	}
+
+	// раздача уже разбужена, если сигнал не помещается
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// broadcastNew читает из БД публикации после последней разосланной и раздает их подписчикам
+func (f *Feed) broadcastNew(ctx context.Context) error {
+	// подписчикам раздаются публикации с любой ценой и категорией, их фильтры применяет broadcast
+	filter := advertisements.AdvertisementFilter{MaxPrice: math.MaxFloat64}
+	for {
+		pubs, err := repository.GetPublications(ctx, f.lastID, &filter, pageSize)
+		if err != nil {
+			return fmt.Errorf("[Feed.broadcastNew] %w", err)
+		}
+		for _, pub := range pubs {
+			f.broadcast(pub)
+			f.lastID = pub.EventID
+		}
+		if len(pubs) < pageSize {
+			return nil
+		}
+	}
+}
+
+// broadcast раздает публикацию подходящим подписчикам
+func (f *Feed) broadcast(pub *advertisements.Publication) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for sub := range f.subs {
+		if !sub.filter.Match(pub.Advertisement) {
+			continue
+		}
+
+		select {
+		case sub.events <- pub:
+		default:
+			// отставший подписчик отключается: клиент переподключится с Last-Event-ID
+			// и получит пропущенное из БД
+			logger.L.Warn("[Feed.broadcast]: subscriber buffer is full, drop subscriber", "event_id", pub.EventID)
+			f.unsubscribe(sub)
+		}
+	}
+}
+
+// Subscribe подписывает на публикации, подходящие под filter
+func (f *Feed) Subscribe(filter Filter) *Subscription {
+	events := make(chan *advertisements.Publication, f.buffer)
+	sub := &Subscription{
+		Events: events,
+		events: events,
+		filter: filter,
+		feed:   f,
+	}
+
+	f.mu.Lock()
+	f.subs[sub] = struct{}{}
+	f.mu.Unlock()
+	return sub
+}
+
+// Close отписывает от потока, повторный вызов ничего не делает
+func (s *Subscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	s.feed.unsubscribe(s)
+}
+
+// unsubscribe вызывается под блокировкой, чтобы Publish не отправил в закрытый канал
+func (f *Feed) unsubscribe(sub *Subscription) {
+	if _, ok := f.subs[sub]; !ok {
+		return
+	}
+	delete(f.subs, sub)
+	close(sub.events)
+}
@@ -0,0 +1,74 @@
+package matching
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/notifications"
+	"github.com/vk_intern/internal/notify"
+	"github.com/vk_intern/internal/repository"
+)
+
+// Matcher в фоне сверяет опубликованные объявления с сохраненными поисками
+// и уведомляет владельцев подходящих поисков
+type Matcher struct {
+	queue chan int
+}
+
+func NewMatcher(queueSize int) *Matcher {
+	return &Matcher{
+		queue: make(chan int, queueSize),
+	}
+}
+
+// Run запускает workers обработчиков очереди, обработчики завершаются при отмене ctx
+func (m *Matcher) Run(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-m.queue:
+					if err := m.Match(ctx, id); err != nil {
+						logger.L.Error("[Matcher.Run | match]:", "id", id, "error", err)
+					}
+				}
+			}
+		}()
+	}
+}
+
+// Enqueue ставит опубликованное объявление в очередь на сверку. Если очередь заполнена,
+// объявление пропускается и уведомления по нему не придут
+func (m *Matcher) Enqueue(id int) {
+	select {
+	case m.queue <- id:
+	default:
+		logger.L.Error("[Matcher.Enqueue]: queue is full, skip saved searches", "id", id)
+	}
+}
+
+// Match отправляет уведомления владельцам сохраненных поисков, под которые подходит объявление id
+func (m *Matcher) Match(ctx context.Context, id int) error {
+	matches, err := repository.MatchSavedSearches(ctx, id)
+	if err != nil {
+		return fmt.Errorf("[Matcher.Match] %w", err)
+	}
+
+	for _, match := range matches {
+		err := notify.SavedSearchMatch(ctx, match.Login, notifications.SavedSearchMatchPayload{
+			SavedSearchID:   match.SavedSearchID,
+			SavedSearchName: match.SavedSearchName,
+			AdvertisementID: match.AdvertisementID,
+			Title:           match.Title,
+			Price:           match.Price,
+		})
+		if err != nil {
+			// совпадение уже сохранено, поэтому остальным владельцам поисков уведомления все равно отправляем
+			logger.L.Error("[Matcher.Match | notify]:", "id", id, "login", match.Login, "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxLenText = 2000
+
+	// длина превью последнего сообщения в списке переписок
+	lenPreview = 100
+)
+
+var (
+	ErrEmptyText = errors.New("message text is required")
+	ErrLongText  = errors.New("message text is longer than required")
+)
+
+func ValidateMessage(req *SendMessageRequest) error {
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		return fmt.Errorf("[ValidateMessage|text]: %w", ErrEmptyText)
+	}
+	if utf8.RuneCountInString(req.Text) > maxLenText {
+		return fmt.Errorf("[ValidateMessage|text]: %w", ErrLongText)
+	}
+
+	req.Buyer = strings.TrimSpace(req.Buyer)
+	return nil
+}
+
+// Preview обрезает текст сообщения до длины превью
+func Preview(text string) string {
+	if utf8.RuneCountInString(text) <= lenPreview {
+		return text
+	}
+	return string([]rune(text)[:lenPreview]) + "…"
+}
@@ -0,0 +1,58 @@
+package messages
+
+import "time"
+
+// Message сообщение в переписке по объявлению
+// @Description Модель описывает сообщение, read_at заполняется, когда получатель открыл переписку
+type Message struct {
+	ID             int        `json:"id"`
+	ConversationID int        `json:"conversation_id"`
+	Sender         string     `json:"sender"`
+	Text           string     `json:"text"`
+	CreatedAt      time.Time  `json:"created_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+	ReadAt         *time.Time `json:"read_at,omitempty" format:"date-time"`
+}
+
+// SendMessageRequest модель запроса на отправку сообщения
+// @Description Модель описывает текст сообщения. Покупатель пишет продавцу без buyer,
+// @Description продавец отвечает в переписку с покупателем buyer
+type SendMessageRequest struct {
+	Text  string `json:"text" validate:"required" example:"Здравствуйте, торг уместен?"`
+	Buyer string `json:"buyer,omitempty" example:"buyer_login"`
+}
+
+// Conversation переписка покупателя с продавцом по объявлению
+// @Description Модель описывает переписку с превью последнего сообщения и количеством непрочитанных входящих
+type Conversation struct {
+	ID                 int       `json:"id"`
+	AdvertisementID    int       `json:"advertisement_id"`
+	AdvertisementTitle string    `json:"advertisement_title"`
+	Buyer              string    `json:"buyer"`
+	Seller             string    `json:"seller"`
+	LastMessage        *Message  `json:"last_message,omitempty"`
+	Unread             int       `json:"unread"`
+	CreatedAt          time.Time `json:"created_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+	UpdatedAt          time.Time `json:"updated_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+}
+
+// ConversationPage страница переписок
+// @Description Модель описывает страницу переписок, сначала идут переписки с самыми свежими сообщениями,
+// @Description unread - количество всех непрочитанных входящих сообщений
+type ConversationPage struct {
+	Items   []*Conversation `json:"items"`
+	Total   int             `json:"total"`
+	Unread  int             `json:"unread"`
+	Page    int             `json:"page"`
+	Limit   int             `json:"limit"`
+	HasNext bool            `json:"has_next"`
+}
+
+// MessagePage страница сообщений переписки
+// @Description Модель описывает страницу сообщений переписки, новые сообщения идут первыми
+type MessagePage struct {
+	Items   []*Message `json:"items"`
+	Total   int        `json:"total"`
+	Page    int        `json:"page"`
+	Limit   int        `json:"limit"`
+	HasNext bool       `json:"has_next"`
+}
@@ -1,53 +1,119 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/vk_intern/internal/revocation"
+	"github.com/vk_intern/internal/users"
 )
 
 var (
 	ErrInvalidJWT = errors.New("invalid JWT token , authorize")
+	ErrRevokedJWT = errors.New("JWT token has been revoked, authorize")
 )
 
-func GenerateJWTToken(login string, JWTSecret string) (string, error) {
+// TokenClaims данные из проверенного access токена
+type TokenClaims struct {
+	Login     string
+	Role      string
+	JTI       string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// GenerateJWTToken выпускает access токен, подписанный активным ключом набора.
+// Роль попадает в токен, поэтому ее смена действует только на новые токены
+func GenerateJWTToken(login, role string, keys *KeySet, ttl time.Duration) (string, error) {
+	// идентификатор токена, по которому его можно отозвать
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", fmt.Errorf("[GenerateJWTToken| generate jti]: %w", err)
+	}
+
 	claims := jwt.MapClaims{
 		"login": login,
+		"role":  role,
+		"jti":   hex.EncodeToString(jti),
 		"iat":   time.Now().Unix(),
-		"exp":   time.Now().Add(time.Minute * 5).Unix(), // время действия токена
+		"exp":   time.Now().Add(ttl).Unix(), // время действия токена
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// подпись токена
-	tokenString, err := token.SignedString([]byte(JWTSecret))
+	tokenString, err := keys.Sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("[GenerateJWTToken| sign token]: %w", err)
 	}
 	return tokenString, nil
 }
 
-func checkTokenIsValid(tokenString, JWTsecret string) error {
-	if tokenString != "" {
-		token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-			return []byte(JWTsecret), nil
-		})
+// ParseToken проверяет подпись и срок действия токена, а также что он не отозван
+func ParseToken(ctx context.Context, tokenString string, keys *KeySet) (*TokenClaims, error) {
+	token, err := jwt.Parse(tokenString, keys.keyFunc)
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidJWT
+	}
 
-		if err != nil || !token.Valid {
-			return ErrInvalidJWT
-		}
+	mapClaims, _ := token.Claims.(jwt.MapClaims)
+	login, _ := mapClaims["login"].(string)
+	role, _ := mapClaims["role"].(string)
+	jti, _ := mapClaims["jti"].(string)
+	iat, _ := mapClaims["iat"].(float64)
+	exp, _ := mapClaims["exp"].(float64)
+	if login == "" || jti == "" {
+		return nil, ErrInvalidJWT
+	}
+	// токены, выпущенные до появления ролей, принадлежат обычным пользователям
+	if role == "" {
+		role = users.RoleUser
+	}
 
+	claims := &TokenClaims{
+		Login:     login,
+		Role:      role,
+		JTI:       jti,
+		IssuedAt:  time.Unix(int64(iat), 0),
+		ExpiresAt: time.Unix(int64(exp), 0),
 	}
-	return nil
+
+	revoked, err := revocation.IsRevoked(ctx, claims.JTI, claims.Login, claims.IssuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[ParseToken| check revoked]: %w", err)
+	}
+	if revoked {
+		return nil, ErrRevokedJWT
+	}
+	return claims, nil
 }
 
-func getLoginFromValidToken(tokenString, JWTsecret string) interface{} {
-	token, _ := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(JWTsecret), nil
-	})
+// GenerateRefreshToken создает случайный refresh токен. Клиенту отдается сам токен,
+// а в БД хранится только его хеш
+func GenerateRefreshToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("[GenerateRefreshToken| read rand]: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), nil
+}
 
-	claims, _ := token.Claims.(jwt.MapClaims)
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
 
-	return claims["login"].(string)
+// GenerateTokenFamily создает идентификатор цепочки refresh токенов, выданных при одном входе
+func GenerateTokenFamily() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("[GenerateTokenFamily| read rand]: %w", err)
+	}
+	return hex.EncodeToString(b), nil
 }
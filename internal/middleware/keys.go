@@ -0,0 +1,234 @@
+package middleware
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// поддерживаемые алгоритмы подписи токенов
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown JWT signing algorithm")
+	ErrNoSigningKey     = errors.New("JWT signing key is not configured")
+	ErrWrongKeySpec     = errors.New("wrong JWT key spec, expected kid:path")
+	ErrUnknownKID       = errors.New("unknown JWT key id")
+	ErrInvalidKeyType   = errors.New("unsupported JWT key type")
+)
+
+// verifyKey ключ проверки подписи вместе с алгоритмом, которым им можно подписывать
+type verifyKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+// KeySet ключи для подписи и проверки токенов. Токены подписываются одним активным ключом,
+// а проверяются любым из известных ключей по kid из заголовка токена: так старые ключи
+// продолжают принимать выданные ими токены, пока их не уберут из конфигурации.
+// Токены без kid проверяются секретом HS256, только если им подписываются новые токены
+// или явно включен переход с HS256
+type KeySet struct {
+	signingMethod jwt.SigningMethod
+	signingKID    string
+	signingKey    interface{}
+
+	secret []byte
+	keys   map[string]verifyKey
+}
+
+// NewKeySet загружает ключи из PEM файлов. privateKeys и publicKeys задаются в виде "kid:путь",
+// публичные ключи используются только для проверки (ключи, выведенные из ротации).
+// Для HS256 подпись делается секретом secret, PEM ключи при этом продолжают проверять токены.
+// При подписи RS256 или EdDSA токены без kid отклоняются, если не включен hs256Fallback
+func NewKeySet(algorithm, secret, signingKID string, privateKeys, publicKeys []string, hs256Fallback bool) (*KeySet, error) {
+	ks := &KeySet{keys: map[string]verifyKey{}}
+	if secret != "" && (algorithm == AlgHS256 || hs256Fallback) {
+		ks.secret = []byte(secret)
+	}
+
+	for _, spec := range privateKeys {
+		kid, path, err := parseKeySpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("[NewKeySet|private key] %w", err)
+		}
+
+		method, private, public, err := loadPrivateKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("[NewKeySet|private key %s] %w", kid, err)
+		}
+		ks.keys[kid] = verifyKey{method: method, key: public}
+
+		if kid == signingKID {
+			ks.signingMethod, ks.signingKID, ks.signingKey = method, kid, private
+		}
+	}
+
+	for _, spec := range publicKeys {
+		kid, path, err := parseKeySpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("[NewKeySet|public key] %w", err)
+		}
+
+		method, public, err := loadPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("[NewKeySet|public key %s] %w", kid, err)
+		}
+		ks.keys[kid] = verifyKey{method: method, key: public}
+	}
+
+	switch algorithm {
+	case AlgHS256:
+		if ks.secret == nil {
+			return nil, fmt.Errorf("[NewKeySet|secret]: %w", ErrNoSigningKey)
+		}
+		ks.signingMethod, ks.signingKID, ks.signingKey = jwt.SigningMethodHS256, "", ks.secret
+	case AlgRS256, AlgEdDSA:
+		if ks.signingKey == nil || ks.signingMethod.Alg() != algorithm {
+			return nil, fmt.Errorf("[NewKeySet|signing key %q]: %w", signingKID, ErrNoSigningKey)
+		}
+		if hs256Fallback && ks.secret == nil {
+			return nil, fmt.Errorf("[NewKeySet|hs256 fallback secret]: %w", ErrNoSigningKey)
+		}
+	default:
+		return nil, fmt.Errorf("[NewKeySet|algorithm %q]: %w", algorithm, ErrUnknownAlgorithm)
+	}
+	return ks, nil
+}
+
+// Sign подписывает claims активным ключом
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signingMethod, claims)
+	if ks.signingKID != "" {
+		token.Header["kid"] = ks.signingKID
+	}
+
+	tokenString, err := token.SignedString(ks.signingKey)
+	if err != nil {
+		return "", fmt.Errorf("[KeySet.Sign]: %w", err)
+	}
+	return tokenString, nil
+}
+
+// keyFunc выбирает ключ проверки по kid и не допускает подмены алгоритма в заголовке токена
+func (ks *KeySet) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok || ks.secret == nil {
+			return nil, ErrInvalidJWT
+		}
+		return ks.secret, nil
+	}
+
+	vk, ok := ks.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKID
+	}
+	if t.Method.Alg() != vk.method.Alg() {
+		return nil, ErrInvalidJWT
+	}
+	return vk.key, nil
+}
+
+// JWK публичный ключ в формате JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS набор публичных ключей
+// @Description Модель описывает публичные ключи для проверки подписи токенов (RFC 7517)
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS возвращает публичные ключи всех асимметричных ключей набора. Секрет HS256 не публикуется
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for kid, vk := range ks.keys {
+		jwk := JWK{Kid: kid, Use: "sig", Alg: vk.method.Alg()}
+
+		switch key := vk.key.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(key)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+func parseKeySpec(spec string) (string, string, error) {
+	kid, path, ok := strings.Cut(strings.TrimSpace(spec), ":")
+	if !ok || kid == "" || path == "" {
+		return "", "", fmt.Errorf("[parseKeySpec] %q: %w", spec, ErrWrongKeySpec)
+	}
+	return kid, path, nil
+}
+
+// loadPrivateKey читает закрытый ключ RSA или Ed25519 и определяет по нему алгоритм подписи
+func loadPrivateKey(path string) (jwt.SigningMethod, interface{}, interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("[loadPrivateKey|read] %w", err)
+	}
+
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return jwt.SigningMethodRS256, key, &key.PublicKey, nil
+	}
+
+	key, err := jwt.ParseEdPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("[loadPrivateKey|parse]: key is neither RSA nor Ed25519: %w", err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("[loadPrivateKey|parse]: %w", ErrInvalidKeyType)
+	}
+	return jwt.SigningMethodEdDSA, edKey, edKey.Public(), nil
+}
+
+// loadPublicKey читает открытый ключ RSA или Ed25519 и определяет по нему алгоритм подписи
+func loadPublicKey(path string) (jwt.SigningMethod, interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("[loadPublicKey|read] %w", err)
+	}
+
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return jwt.SigningMethodRS256, key, nil
+	}
+
+	key, err := jwt.ParseEdPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("[loadPublicKey|parse]: key is neither RSA nor Ed25519: %w", err)
+	}
+	return jwt.SigningMethodEdDSA, key, nil
+}
@@ -1,21 +1,30 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
+	"github.com/vk_intern/internal/logger"
 )
 
-func AuthMiddleware(JWTsecret string) func(c *fiber.Ctx) error {
+func AuthMiddleware(keys *KeySet) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 
 		tokenString := c.Get("Authorization")
 
 		if tokenString != "" {
-			err := checkTokenIsValid(tokenString, JWTsecret)
-			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			_, err := ParseToken(context.Background(), tokenString, keys)
+			switch {
+			case err == nil:
+				return c.Status(fiber.StatusAlreadyReported).JSON("already authorized")
+			case errors.Is(err, ErrRevokedJWT):
+				// после выхода можно авторизоваться заново, даже если клиент прислал старый токен
+				return c.Next()
+			default:
+				return tokenError(c, err)
 			}
-
-			return c.Status(fiber.StatusAlreadyReported).JSON("already authorized")
 		}
 
 		return c.Next()
@@ -23,18 +32,19 @@ func AuthMiddleware(JWTsecret string) func(c *fiber.Ctx) error {
 }
 
 // строгий middleware, который не пускает дальше неавторизованных пользователей
-func StrictMiddleware(JWTsecret string) func(c *fiber.Ctx) error {
+func StrictMiddleware(keys *KeySet) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 		tokenString := c.Get("Authorization")
 
 		if tokenString != "" {
-			err := checkTokenIsValid(tokenString, JWTsecret)
+			claims, err := ParseToken(context.Background(), tokenString, keys)
 			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+				return tokenError(c, err)
 			}
 
-			//заносим логин из токена в контекст и переходим к роуту
-			c.Locals("login", getLoginFromValidToken(tokenString, JWTsecret))
+			//заносим логин и данные токена в контекст и переходим к роуту
+			c.Locals("login", claims.Login)
+			c.Locals("claims", claims)
 			return c.Next()
 		}
 
@@ -43,21 +53,80 @@ func StrictMiddleware(JWTsecret string) func(c *fiber.Ctx) error {
 }
 
 // нестрогий middleware, который пропускает и авторизованных и неавторизованных
-func Middleware(JWTsecret string) func(c *fiber.Ctx) error {
+func Middleware(keys *KeySet) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 		tokenString := c.Get("Authorization")
 
 		if tokenString != "" {
-			err := checkTokenIsValid(tokenString, JWTsecret)
+			claims, err := ParseToken(context.Background(), tokenString, keys)
 			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+				return tokenError(c, err)
 			}
 
-			//заносим логин из токена в контекст
-			c.Locals("login", getLoginFromValidToken(tokenString, JWTsecret))
-
+			//заносим логин и данные токена в контекст
+			c.Locals("login", claims.Login)
+			c.Locals("claims", claims)
 		}
 
 		return c.Next()
 	}
 }
+
+// WebSocketMiddleware пускает к WebSocket только авторизованных пользователей. Браузер не может передать
+// заголовок при открытии WebSocket, поэтому токен принимается и в параметре access_token
+func WebSocketMiddleware(keys *KeySet) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return c.Status(fiber.StatusUpgradeRequired).JSON(fiber.Map{"error": "websocket upgrade required"})
+		}
+
+		tokenString := c.Get("Authorization")
+		if tokenString == "" {
+			tokenString = c.Query("access_token")
+		}
+
+		if tokenString != "" {
+			claims, err := ParseToken(context.Background(), tokenString, keys)
+			if err != nil {
+				return tokenError(c, err)
+			}
+
+			//заносим логин и данные токена в контекст и переходим к роуту
+			c.Locals("login", claims.Login)
+			c.Locals("claims", claims)
+			return c.Next()
+		}
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+}
+
+// RequireRole пускает дальше только пользователей с одной из ролей, ставится после StrictMiddleware
+func RequireRole(roles ...string) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("claims").(*TokenClaims)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+		}
+
+		for _, role := range roles {
+			if claims.Role == role {
+				return c.Next()
+			}
+		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+	}
+}
+
+// tokenError переводит ошибку проверки токена в ответ клиенту
+func tokenError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidJWT):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, ErrRevokedJWT):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	default:
+		logger.L.Error("[tokenError | check token]:", "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
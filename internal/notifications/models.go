@@ -0,0 +1,73 @@
+package notifications
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Notification уведомление во внутреннем ящике пользователя
+// @Description Модель описывает уведомление: type определяет событие, payload - его данные
+type Notification struct {
+	ID        int             `json:"id"`
+	Login     string          `json:"-"`
+	Type      string          `json:"type" example:"saved_search_match"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+	ReadAt    *time.Time      `json:"read_at,omitempty" format:"date-time"`
+}
+
+// NotificationPage страница уведомлений
+// @Description Модель описывает страницу уведомлений, новые уведомления идут первыми, unread - количество всех непрочитанных
+type NotificationPage struct {
+	Items   []*Notification `json:"items"`
+	Total   int             `json:"total"`
+	Unread  int             `json:"unread"`
+	Page    int             `json:"page"`
+	Limit   int             `json:"limit"`
+	HasNext bool            `json:"has_next"`
+}
+
+// SavedSearchMatchPayload данные уведомления о новом объявлении по сохраненному поиску
+type SavedSearchMatchPayload struct {
+	SavedSearchID   int     `json:"saved_search_id"`
+	SavedSearchName string  `json:"saved_search_name"`
+	AdvertisementID int     `json:"advertisement_id"`
+	Title           string  `json:"title"`
+	Price           float64 `json:"price"`
+}
+
+// ReadRequest модель запроса на отметку уведомлений прочитанными
+// @Description Модель описывает идентификаторы уведомлений, без них прочитанными отмечаются все уведомления
+type ReadRequest struct {
+	IDs []int `json:"ids,omitempty"`
+}
+
+// ReadResponse модель ответа на отметку уведомлений прочитанными
+// @Description Модель описывает количество оставшихся непрочитанных уведомлений
+type ReadResponse struct {
+	Unread int `json:"unread"`
+}
+
+// ModerationPayload данные уведомления автору о решении модератора по объявлению
+type ModerationPayload struct {
+	AdvertisementID int    `json:"advertisement_id"`
+	Title           string `json:"title"`
+	Reason          string `json:"reason,omitempty"`
+}
+
+// PriceDropPayload данные уведомления о снижении цены объявления из избранного
+type PriceDropPayload struct {
+	AdvertisementID int     `json:"advertisement_id"`
+	Title           string  `json:"title"`
+	OldPrice        float64 `json:"old_price"`
+	Price           float64 `json:"price"`
+}
+
+// NewMessagePayload данные уведомления о новом сообщении в переписке по объявлению
+type NewMessagePayload struct {
+	ConversationID  int    `json:"conversation_id"`
+	AdvertisementID int    `json:"advertisement_id"`
+	Title           string `json:"title"`
+	Sender          string `json:"sender"`
+	Preview         string `json:"preview"`
+}
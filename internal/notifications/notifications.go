@@ -0,0 +1,10 @@
+package notifications
+
+// типы уведомлений
+const (
+	TypeSavedSearchMatch      = "saved_search_match"
+	TypeAdvertisementApproved = "advertisement_approved"
+	TypeAdvertisementRejected = "advertisement_rejected"
+	TypeFavoritePriceDrop     = "favorite_price_drop"
+	TypeNewMessage            = "new_message"
+)
@@ -0,0 +1,119 @@
+// Package notify внутренний API уведомлений: остальные пакеты сервиса кладут через него
+// уведомления во внутренний ящик пользователей (GET /me/notifications). Если подключена доставка
+// в реальном времени, уведомление сразу отправляется и открытым подключениям получателя
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/messages"
+	"github.com/vk_intern/internal/notifications"
+	"github.com/vk_intern/internal/realtime"
+	"github.com/vk_intern/internal/repository"
+)
+
+var publisher realtime.PubSub
+
+// Init подключает доставку уведомлений в реальном времени, без нее уведомления только сохраняются в ящик
+func Init(pub realtime.PubSub) {
+	publisher = pub
+}
+
+// publish отправляет сохраненное уведомление открытым подключениям получателя. Уведомление уже
+// лежит в ящике, поэтому ошибка доставки только логируется
+func publish(ctx context.Context, n *notifications.Notification) {
+	if publisher == nil {
+		return
+	}
+
+	event := realtime.Event{Type: realtime.EventNotification, Payload: n}
+	if err := publisher.Publish(ctx, n.Login, event); err != nil {
+		logger.L.Error("[publish | publish notification]:", "login", n.Login, "error", err)
+	}
+}
+
+// Emit кладет уведомление типа typ с данными payload во внутренний ящик пользователя login
+func Emit(ctx context.Context, login, typ string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("[Emit|marshal payload]: %w", err)
+	}
+
+	n, err := repository.CreateNotification(ctx, login, typ, data)
+	if err != nil {
+		return fmt.Errorf("[Emit] %w", err)
+	}
+
+	publish(ctx, n)
+	return nil
+}
+
+// EmitToFavorites кладет уведомление всем пользователям, добавившим объявление id в избранное, кроме его автора
+func EmitToFavorites(ctx context.Context, id int, typ string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("[EmitToFavorites|marshal payload]: %w", err)
+	}
+
+	created, err := repository.CreateFavoritesNotifications(ctx, id, typ, data)
+	if err != nil {
+		return fmt.Errorf("[EmitToFavorites] %w", err)
+	}
+
+	for _, n := range created {
+		publish(ctx, n)
+	}
+	return nil
+}
+
+// SavedSearchMatch сообщает, что появилось объявление, подходящее под сохраненный поиск
+func SavedSearchMatch(ctx context.Context, login string, payload notifications.SavedSearchMatchPayload) error {
+	return Emit(ctx, login, notifications.TypeSavedSearchMatch, payload)
+}
+
+// AdvertisementApproved сообщает автору, что объявление прошло модерацию и опубликовано
+func AdvertisementApproved(ctx context.Context, adv *advertisements.Advertisement) error {
+	return Emit(ctx, adv.UserLogin, notifications.TypeAdvertisementApproved, notifications.ModerationPayload{
+		AdvertisementID: adv.ID,
+		Title:           adv.Title,
+	})
+}
+
+// AdvertisementRejected сообщает автору, что модератор отклонил объявление, и почему
+func AdvertisementRejected(ctx context.Context, adv *advertisements.Advertisement) error {
+	return Emit(ctx, adv.UserLogin, notifications.TypeAdvertisementRejected, notifications.ModerationPayload{
+		AdvertisementID: adv.ID,
+		Title:           adv.Title,
+		Reason:          adv.ModerationReason,
+	})
+}
+
+// FavoritePriceDrop сообщает пользователям, добавившим объявление в избранное, что его цена снизилась
+func FavoritePriceDrop(ctx context.Context, adv *advertisements.Advertisement, oldPrice float64) error {
+	return EmitToFavorites(ctx, adv.ID, notifications.TypeFavoritePriceDrop, notifications.PriceDropPayload{
+		AdvertisementID: adv.ID,
+		Title:           adv.Title,
+		OldPrice:        oldPrice,
+		Price:           adv.Price,
+	})
+}
+
+// NewMessage сообщает второму участнику переписки о новом сообщении
+func NewMessage(ctx context.Context, conv *messages.Conversation) error {
+	recipient := conv.Seller
+	if conv.LastMessage.Sender == conv.Seller {
+		recipient = conv.Buyer
+	}
+
+	return Emit(ctx, recipient, notifications.TypeNewMessage, notifications.NewMessagePayload{
+		ConversationID:  conv.ID,
+		AdvertisementID: conv.AdvertisementID,
+		Title:           conv.AdvertisementTitle,
+		Sender:          conv.LastMessage.Sender,
+		Preview:         messages.Preview(conv.LastMessage.Text),
+	})
+}
@@ -0,0 +1,66 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vk_intern/internal/logger"
+)
+
+// Hub раздает события подключениям внутри одного процесса
+type Hub struct {
+	mu     sync.RWMutex
+	subs   map[string]map[chan Event]struct{}
+	buffer int
+}
+
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		subs:   map[string]map[chan Event]struct{}{},
+		buffer: buffer,
+	}
+}
+
+// Publish не ждет медленных подключений: если буфер подключения заполнен, событие для него пропускается
+func (h *Hub) Publish(ctx context.Context, login string, event Event) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subs[login] {
+		select {
+		case ch <- event:
+		default:
+			logger.L.Warn("[Hub.Publish]: subscriber buffer is full, skip event", "login", login, "type", event.Type)
+		}
+	}
+	return nil
+}
+
+func (h *Hub) Subscribe(login string) *Subscription {
+	ch := make(chan Event, h.buffer)
+
+	h.mu.Lock()
+	if h.subs[login] == nil {
+		h.subs[login] = map[chan Event]struct{}{}
+	}
+	h.subs[login][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return &Subscription{
+		Events: ch,
+		cancel: func() {
+			once.Do(func() {
+				// канал закрывается под блокировкой, чтобы Publish не отправил в закрытый канал
+				h.mu.Lock()
+				defer h.mu.Unlock()
+
+				delete(h.subs[login], ch)
+				if len(h.subs[login]) == 0 {
+					delete(h.subs, login)
+				}
+				close(ch)
+			})
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package realtime
+
+import "context"
+
+// типы событий, доставляемых клиентам в реальном времени
+const (
+	EventMessage      = "message"
+	EventNotification = "notification"
+	EventTyping       = "typing"
+	// служебное событие: токены пользователя отозваны, открытые подключения перепроверяют свой токен.
+	// Клиентам не отправляется
+	EventRevoked = "revoked"
+)
+
+// Event событие для подключенного пользователя
+type Event struct {
+	Type    string      `json:"type" example:"message"`
+	Payload interface{} `json:"payload"`
+}
+
+// ClientEvent событие, присланное клиентом по WebSocket
+type ClientEvent struct {
+	Type           string `json:"type" example:"typing"`
+	ConversationID int    `json:"conversation_id"`
+}
+
+// TypingPayload данные события о том, что собеседник набирает сообщение
+type TypingPayload struct {
+	ConversationID int    `json:"conversation_id"`
+	Login          string `json:"login"`
+}
+
+// PubSub доставляет события подключенным пользователям. Реализация в памяти процесса - Hub,
+// для нескольких экземпляров сервиса достаточно реализовать этот же интерфейс поверх Postgres LISTEN/NOTIFY
+type PubSub interface {
+	// Publish отправляет событие всем подключениям пользователя login
+	Publish(ctx context.Context, login string, event Event) error
+	// Subscribe подписывает подключение на события пользователя login
+	Subscribe(login string) *Subscription
+}
+
+// Subscription подписка одного подключения, после Close канал Events закрывается
+type Subscription struct {
+	Events <-chan Event
+	cancel func()
+}
+
+// Close отписывает подключение, повторный вызов ничего не делает
+func (s *Subscription) Close() {
+	s.cancel()
+}
@@ -0,0 +1,44 @@
+package reports
+
+import "time"
+
+// Report жалоба на объявление
+// @Description Модель описывает жалобу пользователя на объявление
+type Report struct {
+	ID              int       `json:"id"`
+	AdvertisementID int       `json:"advertisement_id"`
+	Login           string    `json:"login"`
+	Reason          string    `json:"reason" example:"fraud"`
+	Comment         string    `json:"comment,omitempty"`
+	CreatedAt       time.Time `json:"created_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+}
+
+// CreateReportRequest модель запроса на жалобу
+// @Description Модель описывает причину жалобы (fraud, prohibited, wrong_category, duplicate, offensive, other)
+// @Description и необязательный комментарий, для причины other комментарий обязателен
+type CreateReportRequest struct {
+	Reason  string `json:"reason" validate:"required" example:"fraud"`
+	Comment string `json:"comment,omitempty" example:"просит предоплату на карту"`
+}
+
+// ReportedAdvertisement объявление с необработанными жалобами
+// @Description Модель описывает объявление вместе со всеми необработанными жалобами на него
+type ReportedAdvertisement struct {
+	AdvertisementID int       `json:"advertisement_id"`
+	Title           string    `json:"title"`
+	UserLogin       string    `json:"userlogin"`
+	Status          string    `json:"status" example:"hidden"`
+	ReportsCount    int       `json:"reports_count"`
+	LastReportedAt  time.Time `json:"last_reported_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+	Reports         []*Report `json:"reports"`
+}
+
+// ReportedAdvertisementPage страница объявлений с жалобами
+// @Description Модель описывает страницу объявлений с жалобами, сначала идут объявления с наибольшим числом жалоб
+type ReportedAdvertisementPage struct {
+	Items   []*ReportedAdvertisement `json:"items"`
+	Total   int                      `json:"total"`
+	Page    int                      `json:"page"`
+	Limit   int                      `json:"limit"`
+	HasNext bool                     `json:"has_next"`
+}
@@ -0,0 +1,54 @@
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxLenComment = 500
+
+// причины жалоб на объявление
+const (
+	ReasonFraud         = "fraud"
+	ReasonProhibited    = "prohibited"
+	ReasonWrongCategory = "wrong_category"
+	ReasonDuplicate     = "duplicate"
+	ReasonOffensive     = "offensive"
+	ReasonOther         = "other"
+)
+
+var Reasons = []string{ReasonFraud, ReasonProhibited, ReasonWrongCategory, ReasonDuplicate, ReasonOffensive, ReasonOther}
+
+var (
+	ErrUnknownReason = errors.New("unknown report reason")
+	ErrEmptyComment  = errors.New("comment is required for reason other")
+	ErrLongComment   = errors.New("comment is longer than required")
+)
+
+func ValidateReport(report *CreateReportRequest) error {
+	if err := validateReason(report.Reason); err != nil {
+		return fmt.Errorf("[ValidateReport|reason] %w", err)
+	}
+
+	report.Comment = strings.TrimSpace(report.Comment)
+	if utf8.RuneCountInString(report.Comment) > maxLenComment {
+		return fmt.Errorf("[ValidateReport|comment]: %w", ErrLongComment)
+	}
+
+	// для прочих причин без комментария модератору не понять, что не так с объявлением
+	if report.Reason == ReasonOther && report.Comment == "" {
+		return fmt.Errorf("[ValidateReport|comment]: %w", ErrEmptyComment)
+	}
+	return nil
+}
+
+func validateReason(reason string) error {
+	for _, r := range Reasons {
+		if r == reason {
+			return nil
+		}
+	}
+	return fmt.Errorf("[validateReason]: %w", ErrUnknownReason)
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vk_intern/internal/advertisements"
+)
+
+func GetAdvertisementImages(ctx context.Context, advID int) ([]advertisements.AdvertisementImage, error) {
+	images := []advertisements.AdvertisementImage{}
+
+	query := "SELECT url,position,is_cover FROM advertisement_images WHERE advertisement_id = $1 ORDER BY position"
+	rows, err := Pool.Query(ctx, query, advID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetAdvertisementImages|exec get images] %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var im advertisements.AdvertisementImage
+		if err := rows.Scan(&im.URL, &im.Position, &im.IsCover); err != nil {
+			return nil, fmt.Errorf("[GetAdvertisementImages|exec get image] %w", err)
+		}
+		images = append(images, im)
+	}
+	return images, nil
+}
+
+// replaceAdvertisementImages заменяет всю галерею объявления и обновляет обложку в самом объявлении
+func replaceAdvertisementImages(ctx context.Context, tx pgx.Tx, advID int, images []advertisements.AdvertisementImage) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM advertisement_images WHERE advertisement_id = $1", advID); err != nil {
+		return fmt.Errorf("[replaceAdvertisementImages|exec delete images]: %w", err)
+	}
+
+	rows := make([][]interface{}, 0, len(images))
+	for _, im := range images {
+		rows = append(rows, []interface{}{advID, im.URL, im.Position, im.IsCover})
+
+		if im.IsCover {
+			query := "UPDATE advertisements SET image_url = $1 WHERE id = $2"
+			if _, err := tx.Exec(ctx, query, im.URL, advID); err != nil {
+				return fmt.Errorf("[replaceAdvertisementImages|exec update cover]: %w", err)
+			}
+		}
+	}
+
+	_, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"advertisement_images"},
+		[]string{"advertisement_id", "url", "position", "is_cover"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return fmt.Errorf("[replaceAdvertisementImages|copy images]: %w", err)
+	}
+	return nil
+}
+
+// setAdvertisementCover делает обложкой фотографию с позицией coverIndex из уже сохраненной галереи
+func setAdvertisementCover(ctx context.Context, tx pgx.Tx, advID, coverIndex int) error {
+	var count int
+	query := "SELECT COUNT(*) FROM advertisement_images WHERE advertisement_id = $1"
+	if err := tx.QueryRow(ctx, query, advID).Scan(&count); err != nil {
+		return fmt.Errorf("[setAdvertisementCover|exec count images]: %w", err)
+	}
+	if err := advertisements.CoverIndexInRange(coverIndex, count); err != nil {
+		return fmt.Errorf("[setAdvertisementCover|check cover] %w", err)
+	}
+
+	// сначала снимаем старую обложку, иначе сработает уникальный индекс на обложку объявления
+	query = "UPDATE advertisement_images SET is_cover = FALSE WHERE advertisement_id = $1 AND is_cover"
+	if _, err := tx.Exec(ctx, query, advID); err != nil {
+		return fmt.Errorf("[setAdvertisementCover|exec unset cover]: %w", err)
+	}
+
+	query = "UPDATE advertisement_images SET is_cover = TRUE WHERE advertisement_id = $1 AND position = $2"
+	if _, err := tx.Exec(ctx, query, advID, coverIndex); err != nil {
+		return fmt.Errorf("[setAdvertisementCover|exec set cover]: %w", err)
+	}
+
+	query = `UPDATE advertisements SET image_url = 
+			(SELECT url FROM advertisement_images WHERE advertisement_id = $1 AND position = $2)
+			WHERE id = $1`
+	if _, err := tx.Exec(ctx, query, advID, coverIndex); err != nil {
+		return fmt.Errorf("[setAdvertisementCover|exec update cover]: %w", err)
+	}
+	return nil
+}
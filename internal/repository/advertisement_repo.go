@@ -2,59 +2,494 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/vk_intern/internal/advertisements"
 )
 
+var (
+	ErrAdvertisementNotFound = errors.New("advertisement with this id does not exists")
+	ErrNotAdvertisementOwner = errors.New("user is not the owner of advertisement")
+)
+
 func LoadAdvertisement(ctx context.Context, adv *advertisements.CreateAdvertisementRequest) (*advertisements.Advertisement, error) {
-	query := "INSERT INTO advertisements (title,description,price,image_url,login,created_at) VALUES ($1,$2,$3,$4,$5,$6)"
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[LoadAdvertisement|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := "INSERT INTO advertisements (title,description,price,image_url,login,status,category_id,created_at) VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING id"
 
+	var id int
 	created_at := time.Now()
-	if _, err := Pool.Exec(ctx, query, adv.Title, adv.Description, adv.Price, adv.ImageURL, adv.UserLogin, created_at); err != nil {
+	if err := tx.QueryRow(ctx, query, adv.Title, adv.Description, adv.Price, adv.ImageURL, adv.UserLogin, adv.Status, adv.CategoryID, created_at).Scan(&id); err != nil {
+		if isForeignKeyViolation(err, "advertisements_category_id_fkey") {
+			return nil, fmt.Errorf("[LoadAdvertisement|exec load advertisement]: %w", ErrCategoryNotFound)
+		}
 		return nil, fmt.Errorf("[LoadAdvertisement|exec load advertisement]: %w", err)
 	}
 
+	// сохраним галерею объявления
+	images := advertisements.BuildImages(adv.Images, adv.CoverIndex)
+	if err := replaceAdvertisementImages(ctx, tx, id, images); err != nil {
+		return nil, fmt.Errorf("[LoadAdvertisement|load images]: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[LoadAdvertisement|commit tx]: %w", err)
+	}
+
 	return &advertisements.Advertisement{
+		ID:          id,
 		Title:       adv.Title,
 		Description: adv.Description,
 		Price:       adv.Price,
 		ImageURL:    adv.ImageURL,
 		UserLogin:   adv.UserLogin,
+		Status:      adv.Status,
+		CategoryID:  adv.CategoryID,
 		CreatedAt:   created_at,
+		Images:      images,
 	}, nil
 }
 
-func GetAllAdvertisements(ctx context.Context, login string, params *advertisements.AdvertisementFilter) ([]*advertisements.AdvertisementResponse, error) {
-	advs := []*advertisements.AdvertisementResponse{}
+// GetAllAdvertisements возвращает страницу ленты объявлений. Общее количество объявлений
+// считается отдельным запросом в том же обращении к БД, только если оно запрошено в params
+func GetAllAdvertisements(ctx context.Context, login string, params *advertisements.AdvertisementFilter) (*advertisements.AdvertisementPage, error) {
+	page := &advertisements.AdvertisementPage{
+		Items: []*advertisements.AdvertisementResponse{},
+		Limit: params.Limit,
+	}
 
 	order := params.Order
-	order_by := params.OrderBy
-	offset := (params.Page - 1) * params.Limit
-	query := `SELECT title,description,price,image_url,login,created_at 
+	where, args, order_by := advertisementsFilterWhere(login, params)
+
+	batch := &pgx.Batch{}
+	if params.WithTotal {
+		batch.Queue("SELECT COUNT(*) FROM advertisements WHERE "+where, args...).QueryRow(func(row pgx.Row) error {
+			return row.Scan(&page.Total)
+		})
+	}
+
+	// признак избранного считаем в том же запросе, а не отдельно для каждого объявления
+	args = append(args, login)
+	query := `SELECT id,title,description,price,image_url,login,status,COALESCE(category_id, 0),created_at,COALESCE(moderation_reason, ''),
+				` + fmt.Sprintf(isFavoriteExpr, len(args)) + `,` + fmt.Sprintf(sellerRatingExpr, "advertisements.login") + ` 
 			FROM advertisements 
-			WHERE price BETWEEN $1 AND $2
-			ORDER BY ` + order_by + " " + order +
-		` LIMIT $3 OFFSET $4`
+			WHERE ` + where
 
-	rows, err := Pool.Query(ctx, query, params.MinPrice, params.MaxPrice, params.Limit, offset)
-	if err != nil {
+	// продолжаем ленту строго после объявления из курсора, id разрешает равные значения сортировки
+	if params.After != nil {
+		cmp := ">"
+		if order == "DESC" {
+			cmp = "<"
+		}
+
+		var value interface{} = params.After.CreatedAt
+		if order_by == advertisements.OrderByPrice {
+			value = params.After.Price
+		}
+		args = append(args, value, params.After.ID)
+		query += fmt.Sprintf(" AND (%s, id) %s ($%d, $%d)", order_by, cmp, len(args)-1, len(args))
+	}
+
+	query += ` ORDER BY ` + order_by + " " + order + ", id " + order
+	if params.UseCursor {
+		// запрашиваем на одно объявление больше, чтобы узнать, есть ли следующая страница
+		args = append(args, params.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	} else {
+		page.Page = params.Page
+		offset := (params.Page - 1) * params.Limit
+		args = append(args, params.Limit, offset)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	batch.Queue(query, args...).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			var curAdv advertisements.AdvertisementResponse
+			err := rows.Scan(&curAdv.ID, &curAdv.Title, &curAdv.Description, &curAdv.Price, &curAdv.ImageURL, &curAdv.UserLogin, &curAdv.Status, &curAdv.CategoryID, &curAdv.CreatedAt, &curAdv.ModerationReason, &curAdv.IsFavorite, &curAdv.SellerRating)
+			if err != nil {
+				return fmt.Errorf("[GetAllAdvertisements|exec get adv] %w", err)
+			}
+
+			if curAdv.UserLogin == login {
+				curAdv.IsMine = true
+			}
+			curAdv.Thumbnails = advertisements.ThumbnailURLs(curAdv.ImageURL)
+
+			page.Items = append(page.Items, &curAdv)
+		}
+		return rows.Err()
+	})
+
+	if err := Pool.SendBatch(ctx, batch).Close(); err != nil {
 		return nil, fmt.Errorf("[GetAllAdvertisements|exec get advs] %w", err)
 	}
 
-	for rows.Next() {
-		var curAdv advertisements.AdvertisementResponse
-		err := rows.Scan(&curAdv.Title, &curAdv.Description, &curAdv.Price, &curAdv.ImageURL, &curAdv.UserLogin, &curAdv.CreatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("[GetAllAdvertisements|exec get adv] %w", err)
+	if params.UseCursor {
+		if len(page.Items) > params.Limit {
+			page.Items = page.Items[:params.Limit]
+			page.NextCursor = advertisements.NewCursor(params, page.Items[len(page.Items)-1]).Encode()
+			page.HasNext = true
+		}
+	} else {
+		page.HasNext = params.Page*params.Limit < page.Total
+	}
+	return page, nil
+}
+
+// advertisementsFilterWhere собирает условие WHERE и его аргументы по параметрам фильтрации,
+// а также выражение для ORDER BY
+func advertisementsFilterWhere(login string, params *advertisements.AdvertisementFilter) (string, []interface{}, string) {
+	order_by := params.OrderBy
+	where := "price BETWEEN $1 AND $2 AND status = $3"
+	args := []interface{}{params.MinPrice, params.MaxPrice, params.Status}
+
+	// черновики, архивные и не прошедшие модерацию объявления видит только их автор
+	if !advertisements.IsPublicStatus(params.Status) {
+		args = append(args, login)
+		where += fmt.Sprintf(" AND login = $%d", len(args))
+	}
+
+	// фильтр по категории вместе со всеми ее подкатегориями
+	if params.Category != "" {
+		args = append(args, params.Category)
+		where += " AND category_id IN " + fmt.Sprintf(categorySubtreeQuery, len(args))
+	}
+
+	// полнотекстовый поиск по заголовку и описанию с русской и английской морфологией
+	if params.Query != "" {
+		args = append(args, params.Query)
+		tsQuery := fmt.Sprintf("(websearch_to_tsquery('russian', $%d) || websearch_to_tsquery('english', $%d))", len(args), len(args))
+		where += " AND search_vector @@ " + tsQuery
+
+		if order_by == advertisements.OrderByRelevance {
+			order_by = "ts_rank(search_vector, " + tsQuery + ")"
+		}
+	}
+	return where, args, order_by
+}
+
+func GetAdvertisementByID(ctx context.Context, login string, id int) (*advertisements.AdvertisementResponse, error) {
+	query := `SELECT id,title,description,price,image_url,login,status,COALESCE(category_id, 0),created_at,COALESCE(moderation_reason, ''),
+				` + fmt.Sprintf(isFavoriteExpr, 2) + `,` + fmt.Sprintf(sellerRatingExpr, "advertisements.login") + ` 
+			FROM advertisements 
+			WHERE id = $1`
+
+	var adv advertisements.AdvertisementResponse
+	err := Pool.QueryRow(ctx, query, id, login).Scan(&adv.ID, &adv.Title, &adv.Description, &adv.Price, &adv.ImageURL, &adv.UserLogin, &adv.Status, &adv.CategoryID, &adv.CreatedAt, &adv.ModerationReason, &adv.IsFavorite, &adv.SellerRating)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[GetAdvertisementByID|exec get adv]: %w", ErrAdvertisementNotFound)
 		}
+		return nil, fmt.Errorf("[GetAdvertisementByID|exec get adv]: %w", err)
+	}
+
+	// непубличные объявления для остальных пользователей как будто не существуют
+	if !advertisements.IsPublicStatus(adv.Status) && adv.UserLogin != login {
+		return nil, fmt.Errorf("[GetAdvertisementByID|check status]: %w", ErrAdvertisementNotFound)
+	}
+
+	if adv.UserLogin == login {
+		adv.IsMine = true
+	} else {
+		adv.ModerationReason = ""
+	}
+	adv.Thumbnails = advertisements.ThumbnailURLs(adv.ImageURL)
+
+	// в карточке объявления отдаем всю галерею
+	adv.Images, err = GetAdvertisementImages(ctx, adv.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetAdvertisementByID|get images]: %w", err)
+	}
+
+	return &adv, nil
+}
+
+// GetMyAdvertisements возвращает страницу объявлений пользователя во всех статусах (или в статусе status,
+// если он задан) вместе со статистикой, новые объявления идут первыми
+func GetMyAdvertisements(ctx context.Context, login, status string, page, limit int) (*advertisements.AdvertisementPage, error) {
+	resp := &advertisements.AdvertisementPage{
+		Items: []*advertisements.AdvertisementResponse{},
+		Page:  page,
+		Limit: limit,
+	}
+
+	where := "login = $1"
+	args := []interface{}{login}
+	if status != "" {
+		args = append(args, status)
+		where += " AND status = $2"
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("SELECT COUNT(*) FROM advertisements WHERE "+where, args...).QueryRow(func(row pgx.Row) error {
+		return row.Scan(&resp.Total)
+	})
+
+	query := `SELECT id,title,description,price,image_url,login,status,COALESCE(category_id, 0),created_at,COALESCE(moderation_reason, ''),
+				views_count + (SELECT COUNT(*) FROM advertisement_views v WHERE v.advertisement_id = advertisements.id),
+				(SELECT COUNT(*) FROM favorites f WHERE f.advertisement_id = advertisements.id),
+				(SELECT COUNT(*) FROM conversations c WHERE c.advertisement_id = advertisements.id)
+			FROM advertisements
+			WHERE ` + where + fmt.Sprintf(`
+			ORDER BY created_at DESC, id DESC
+			LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	args = append(args, limit, (page-1)*limit)
+
+	batch.Queue(query, args...).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			curAdv := advertisements.AdvertisementResponse{Stats: &advertisements.AdvertisementStats{}}
+			err := rows.Scan(&curAdv.ID, &curAdv.Title, &curAdv.Description, &curAdv.Price, &curAdv.ImageURL, &curAdv.UserLogin, &curAdv.Status, &curAdv.CategoryID, &curAdv.CreatedAt, &curAdv.ModerationReason,
+				&curAdv.Stats.Views, &curAdv.Stats.Favorites, &curAdv.Stats.Conversations)
+			if err != nil {
+				return fmt.Errorf("[GetMyAdvertisements|exec get adv] %w", err)
+			}
 
-		if curAdv.UserLogin == login {
 			curAdv.IsMine = true
+			curAdv.Thumbnails = advertisements.ThumbnailURLs(curAdv.ImageURL)
+
+			resp.Items = append(resp.Items, &curAdv)
 		}
+		return rows.Err()
+	})
+
+	if err := Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("[GetMyAdvertisements|exec get advs] %w", err)
+	}
+
+	resp.HasNext = page*limit < resp.Total
+	return resp, nil
+}
 
-		advs = append(advs, &curAdv)
+// RecordAdvertisementView засчитывает просмотр карточки объявления зрителем viewer. Повторные
+// просмотры одного зрителя в течение дня засчитываются один раз. Просмотры хранятся отдельно
+// от объявлений, чтобы каждый показ карточки не переписывал строку объявления
+func RecordAdvertisementView(ctx context.Context, id int, viewer string) error {
+	query := "INSERT INTO advertisement_views (advertisement_id,viewer) VALUES ($1,$2) ON CONFLICT DO NOTHING"
+	if _, err := Pool.Exec(ctx, query, id, viewer); err != nil {
+		return fmt.Errorf("[RecordAdvertisementView|exec record view]: %w", err)
 	}
-	return advs, nil
+	return nil
+}
+
+// AggregateAdvertisementViews удаляет просмотры старше retentionDays дней, прибавляя их к счетчикам
+// объявлений views_count. Повторные просмотры к этому времени уже отсеяны по дням, поэтому
+// статистика не меняется. Возвращает число объявлений, чьи счетчики обновлены
+func AggregateAdvertisementViews(ctx context.Context, retentionDays int) (int64, error) {
+	query := `WITH old AS (
+				DELETE FROM advertisement_views WHERE viewed_on < CURRENT_DATE - $1::int RETURNING advertisement_id
+			), counts AS (
+				SELECT advertisement_id, COUNT(*) AS views FROM old GROUP BY advertisement_id
+			)
+			UPDATE advertisements a SET views_count = a.views_count + c.views
+			FROM counts c
+			WHERE a.id = c.advertisement_id`
+
+	tag, err := Pool.Exec(ctx, query, retentionDays)
+	if err != nil {
+		return 0, fmt.Errorf("[AggregateAdvertisementViews|exec aggregate views]: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// UpdateAdvertisement изменяет переданные поля объявления и возвращает его вместе с ценой до изменения.
+// При премодерации изменение заголовка, описания, категории или фотографий опубликованного
+// объявления отправляет его в очередь модерации
+func UpdateAdvertisement(ctx context.Context, login string, id int, upd *advertisements.UpdateAdvertisementRequest, premoderation bool) (*advertisements.Advertisement, float64, error) {
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("[UpdateAdvertisement|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// проверим, что объявление существует и принадлежит пользователю
+	curStatus, err := checkAdvertisementOwner(ctx, tx, login, id)
+	if err != nil {
+		return nil, 0, fmt.Errorf("[UpdateAdvertisement|check owner]: %w", err)
+	}
+
+	// строка уже заблокирована, цена до изменения нужна для уведомлений о снижении цены,
+	// а текущее содержимое - чтобы понять, нужна ли повторная модерация
+	var (
+		old       advertisements.Advertisement
+		oldImages []string
+	)
+	query := `SELECT price,title,description,COALESCE(category_id, 0),
+				COALESCE((SELECT array_agg(url ORDER BY position) FROM advertisement_images WHERE advertisement_id = $1), '{}')
+			FROM advertisements WHERE id = $1`
+	if err := tx.QueryRow(ctx, query, id).Scan(&old.Price, &old.Title, &old.Description, &old.CategoryID, &oldImages); err != nil {
+		return nil, 0, fmt.Errorf("[UpdateAdvertisement|exec get current]: %w", err)
+	}
+	oldPrice := old.Price
+
+	// соберем список изменяемых полей
+	sets := []string{}
+	args := []interface{}{}
+	addSet := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if upd.Title != nil {
+		addSet("title", *upd.Title)
+	}
+	if upd.Description != nil {
+		addSet("description", *upd.Description)
+	}
+	if upd.Price != nil {
+		addSet("price", *upd.Price)
+	}
+	if upd.CategoryID != nil {
+		addSet("category_id", *upd.CategoryID)
+	}
+
+	contentChanged := (upd.Title != nil && *upd.Title != old.Title) ||
+		(upd.Description != nil && *upd.Description != old.Description) ||
+		(upd.CategoryID != nil && *upd.CategoryID != old.CategoryID) ||
+		(upd.Images != nil && !slices.Equal(*upd.Images, oldImages))
+	if premoderation && contentChanged {
+		if status := advertisements.ApplyEditPremoderation(curStatus); status != curStatus {
+			addSet("status", status)
+			addSet("moderation_reason", nil)
+		}
+	}
+
+	// галерею и обложку меняем до основного UPDATE, чтобы он вернул актуальный image_url
+	if upd.Images != nil {
+		images := advertisements.BuildImages(*upd.Images, *upd.CoverIndex)
+		if err := replaceAdvertisementImages(ctx, tx, id, images); err != nil {
+			return nil, 0, fmt.Errorf("[UpdateAdvertisement|replace images]: %w", err)
+		}
+	} else if upd.CoverIndex != nil {
+		if err := setAdvertisementCover(ctx, tx, id, *upd.CoverIndex); err != nil {
+			return nil, 0, fmt.Errorf("[UpdateAdvertisement|set cover]: %w", err)
+		}
+	}
+
+	columns := "id,title,description,price,image_url,login,status,COALESCE(category_id, 0),created_at"
+	if len(sets) == 0 {
+		// если менялись только фотографии, обновлять в самом объявлении нечего, читаем его как есть
+		args = []interface{}{id}
+		query = "SELECT " + columns + " FROM advertisements WHERE id = $1"
+	} else {
+		args = append(args, id)
+		query = "UPDATE advertisements SET " + strings.Join(sets, ", ") +
+			fmt.Sprintf(" WHERE id = $%d", len(args)) +
+			" RETURNING " + columns
+	}
+
+	var adv advertisements.Advertisement
+	err = tx.QueryRow(ctx, query, args...).Scan(&adv.ID, &adv.Title, &adv.Description, &adv.Price, &adv.ImageURL, &adv.UserLogin, &adv.Status, &adv.CategoryID, &adv.CreatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err, "advertisements_category_id_fkey") {
+			return nil, 0, fmt.Errorf("[UpdateAdvertisement|exec update adv]: %w", ErrCategoryNotFound)
+		}
+		return nil, 0, fmt.Errorf("[UpdateAdvertisement|exec update adv]: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, 0, fmt.Errorf("[UpdateAdvertisement|commit tx]: %w", err)
+	}
+
+	adv.Images, err = GetAdvertisementImages(ctx, id)
+	if err != nil {
+		return nil, 0, fmt.Errorf("[UpdateAdvertisement|get images]: %w", err)
+	}
+	return &adv, oldPrice, nil
+}
+
+func DeleteAdvertisement(ctx context.Context, login string, id int) error {
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[DeleteAdvertisement|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// проверим, что объявление существует и принадлежит пользователю
+	if _, err := checkAdvertisementOwner(ctx, tx, login, id); err != nil {
+		return fmt.Errorf("[DeleteAdvertisement|check owner]: %w", err)
+	}
+
+	query := "DELETE FROM advertisements WHERE id = $1"
+	if _, err := tx.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("[DeleteAdvertisement|exec delete adv]: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[DeleteAdvertisement|commit tx]: %w", err)
+	}
+	return nil
+}
+
+// ChangeAdvertisementStatus меняет статус объявления по запросу автора. При премодерации
+// публикация объявления отправляет его в очередь модерации
+func ChangeAdvertisementStatus(ctx context.Context, login string, id int, status string, premoderation bool) (*advertisements.Advertisement, error) {
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[ChangeAdvertisementStatus|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// проверим, что объявление существует и принадлежит пользователю
+	curStatus, err := checkAdvertisementOwner(ctx, tx, login, id)
+	if err != nil {
+		return nil, fmt.Errorf("[ChangeAdvertisementStatus|check owner]: %w", err)
+	}
+
+	// проверим, что переход из текущего статуса разрешен
+	if err := advertisements.ValidateStatusTransition(curStatus, status); err != nil {
+		return nil, fmt.Errorf("[ChangeAdvertisementStatus|check transition]: %w", err)
+	}
+	if premoderation {
+		status = advertisements.ApplyPremoderation(curStatus, status)
+	}
+
+	// при повторной отправке на модерацию прошлая причина отклонения больше не актуальна
+	query := `UPDATE advertisements SET status = $1,
+				moderation_reason = CASE WHEN $1 = 'pending' THEN NULL ELSE moderation_reason END
+			WHERE id = $2
+			RETURNING id,title,description,price,image_url,login,status,COALESCE(category_id, 0),created_at,COALESCE(moderation_reason, '')`
+
+	var adv advertisements.Advertisement
+	err = tx.QueryRow(ctx, query, status, id).Scan(&adv.ID, &adv.Title, &adv.Description, &adv.Price, &adv.ImageURL, &adv.UserLogin, &adv.Status, &adv.CategoryID, &adv.CreatedAt, &adv.ModerationReason)
+	if err != nil {
+		return nil, fmt.Errorf("[ChangeAdvertisementStatus|exec update status]: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[ChangeAdvertisementStatus|commit tx]: %w", err)
+	}
+	return &adv, nil
+}
+
+// checkAdvertisementOwner блокирует строку объявления до конца транзакции,
+// проверяет, что его автор совпадает с login, и возвращает текущий статус объявления
+func checkAdvertisementOwner(ctx context.Context, tx pgx.Tx, login string, id int) (string, error) {
+	var owner, status string
+	query := "SELECT login,status FROM advertisements WHERE id = $1 FOR UPDATE"
+	if err := tx.QueryRow(ctx, query, id).Scan(&owner, &status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("[checkAdvertisementOwner|exec get owner]: %w", ErrAdvertisementNotFound)
+		}
+		return "", fmt.Errorf("[checkAdvertisementOwner|exec get owner]: %w", err)
+	}
+
+	if owner != login {
+		return "", fmt.Errorf("[checkAdvertisementOwner|compare owner]: %w", ErrNotAdvertisementOwner)
+	}
+	return status, nil
+}
+
+// isForeignKeyViolation проверяет, что ошибка вызвана нарушением указанного внешнего ключа
+func isForeignKeyViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.ConstraintName == constraint
 }
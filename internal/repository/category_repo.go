@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vk_intern/internal/categories"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category does not exists")
+)
+
+func GetAllCategories(ctx context.Context) ([]*categories.Category, error) {
+	cats := []*categories.Category{}
+
+	query := "SELECT id,parent_id,slug,name FROM categories ORDER BY id"
+	rows, err := Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("[GetAllCategories|exec get categories] %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cat categories.Category
+		if err := rows.Scan(&cat.ID, &cat.ParentID, &cat.Slug, &cat.Name); err != nil {
+			return nil, fmt.Errorf("[GetAllCategories|exec get category] %w", err)
+		}
+		cats = append(cats, &cat)
+	}
+	return cats, nil
+}
+
+func CheckCategoryExists(ctx context.Context, slug string) (bool, error) {
+	var exists bool
+	query := "SELECT EXISTS(SELECT 1 FROM categories WHERE slug = $1)"
+	if err := Pool.QueryRow(ctx, query, slug).Scan(&exists); err != nil {
+		return false, fmt.Errorf("[CheckCategoryExists|exec check exists]: %w", err)
+	}
+	return exists, nil
+}
+
+// categorySubtreeQuery подзапрос с идентификаторами категории по slug и всех ее потомков,
+// на место %d подставляется номер параметра со slug
+const categorySubtreeQuery = `(WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE slug = $%d
+				UNION ALL
+				SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+			) SELECT id FROM tree)`
+
+// GetCategorySubtree возвращает идентификаторы категории по slug и всех ее потомков
+func GetCategorySubtree(ctx context.Context, slug string) ([]int, error) {
+	rows, err := Pool.Query(ctx, "SELECT id FROM "+fmt.Sprintf(categorySubtreeQuery, 1)+" AS subtree", slug)
+	if err != nil {
+		return nil, fmt.Errorf("[GetCategorySubtree|exec get subtree]: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("[GetCategorySubtree|exec get id]: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetCategorySubtree|exec get subtree]: %w", err)
+	}
+	return ids, nil
+}
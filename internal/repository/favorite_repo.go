@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vk_intern/internal/advertisements"
+)
+
+// isFavoriteExpr выражение для списка выбираемых полей объявления, добавлено ли оно пользователем
+// в избранное, на место %d подставляется номер параметра с логином
+const isFavoriteExpr = `EXISTS(SELECT 1 FROM favorites f WHERE f.advertisement_id = advertisements.id AND f.login = $%d)`
+
+// AddFavorite добавляет объявление в избранное пользователя, повторное добавление ничего не меняет
+func AddFavorite(ctx context.Context, login string, id int) error {
+	// добавить в избранное можно только объявление, которое пользователь может видеть
+	var owner, status string
+	query := "SELECT login,status FROM advertisements WHERE id = $1"
+	if err := Pool.QueryRow(ctx, query, id).Scan(&owner, &status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("[AddFavorite|exec get adv]: %w", ErrAdvertisementNotFound)
+		}
+		return fmt.Errorf("[AddFavorite|exec get adv]: %w", err)
+	}
+	if !advertisements.IsPublicStatus(status) && owner != login {
+		return fmt.Errorf("[AddFavorite|check status]: %w", ErrAdvertisementNotFound)
+	}
+
+	query = "INSERT INTO favorites (login,advertisement_id,created_at) VALUES ($1,$2,$3) ON CONFLICT DO NOTHING"
+	if _, err := Pool.Exec(ctx, query, login, id, time.Now()); err != nil {
+		// объявление могли удалить между проверкой и добавлением
+		if isForeignKeyViolation(err, "favorites_advertisement_id_fkey") {
+			return fmt.Errorf("[AddFavorite|exec add favorite]: %w", ErrAdvertisementNotFound)
+		}
+		return fmt.Errorf("[AddFavorite|exec add favorite]: %w", err)
+	}
+	return nil
+}
+
+// RemoveFavorite убирает объявление из избранного пользователя, если оно там было
+func RemoveFavorite(ctx context.Context, login string, id int) error {
+	query := "DELETE FROM favorites WHERE login = $1 AND advertisement_id = $2"
+	if _, err := Pool.Exec(ctx, query, login, id); err != nil {
+		return fmt.Errorf("[RemoveFavorite|exec remove favorite]: %w", err)
+	}
+	return nil
+}
+
+// GetFavorites возвращает страницу избранного пользователя, последние добавленные объявления идут первыми.
+// Объявления, ставшие непубличными, не показываются, пока снова не станут видны
+func GetFavorites(ctx context.Context, login string, params *advertisements.AdvertisementFilter) (*advertisements.AdvertisementPage, error) {
+	page := &advertisements.AdvertisementPage{
+		Items: []*advertisements.AdvertisementResponse{},
+		Page:  params.Page,
+		Limit: params.Limit,
+	}
+
+	where := "f.login = $1 AND (a.status = ANY($2) OR a.login = $1)"
+	args := []interface{}{login, advertisements.PublicStatuses}
+
+	batch := &pgx.Batch{}
+	batch.Queue("SELECT COUNT(*) FROM favorites f JOIN advertisements a ON a.id = f.advertisement_id WHERE "+where, args...).QueryRow(func(row pgx.Row) error {
+		return row.Scan(&page.Total)
+	})
+
+	query := `SELECT a.id,a.title,a.description,a.price,a.image_url,a.login,a.status,COALESCE(a.category_id, 0),a.created_at,
+				` + fmt.Sprintf(sellerRatingExpr, "a.login") + ` 
+			FROM favorites f
+			JOIN advertisements a ON a.id = f.advertisement_id
+			WHERE ` + where + `
+			ORDER BY f.created_at DESC, a.id DESC
+			LIMIT $3 OFFSET $4`
+
+	offset := (params.Page - 1) * params.Limit
+	batch.Queue(query, login, advertisements.PublicStatuses, params.Limit, offset).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			var curAdv advertisements.AdvertisementResponse
+			err := rows.Scan(&curAdv.ID, &curAdv.Title, &curAdv.Description, &curAdv.Price, &curAdv.ImageURL, &curAdv.UserLogin, &curAdv.Status, &curAdv.CategoryID, &curAdv.CreatedAt, &curAdv.SellerRating)
+			if err != nil {
+				return fmt.Errorf("[GetFavorites|exec get adv] %w", err)
+			}
+
+			if curAdv.UserLogin == login {
+				curAdv.IsMine = true
+			}
+			curAdv.IsFavorite = true
+			curAdv.Thumbnails = advertisements.ThumbnailURLs(curAdv.ImageURL)
+
+			page.Items = append(page.Items, &curAdv)
+		}
+		return rows.Err()
+	})
+
+	if err := Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("[GetFavorites|exec get advs] %w", err)
+	}
+
+	page.HasNext = params.Page*params.Limit < page.Total
+	return page, nil
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/messages"
+	"github.com/vk_intern/internal/users"
+)
+
+var (
+	ErrConversationNotFound       = errors.New("conversation not found")
+	ErrNotConversationParticipant = errors.New("user is not a participant of the conversation")
+	ErrMessageOwnAdvertisement    = errors.New("seller can not start conversation on own advertisement")
+	ErrMessagesDisabled           = errors.New("seller does not accept messages")
+)
+
+// SendMessage отправляет сообщение в переписку по объявлению id. Покупатель пишет продавцу, при первом
+// сообщении переписка создается (только по опубликованному объявлению). Продавец может только отвечать
+// в существующую переписку с покупателем req.Buyer. Возвращает переписку с отправленным сообщением
+func SendMessage(ctx context.Context, login string, id int, req *messages.SendMessageRequest) (*messages.Conversation, error) {
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[SendMessage|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	conv := &messages.Conversation{AdvertisementID: id}
+
+	var status string
+	query := "SELECT login,title,status FROM advertisements WHERE id = $1"
+	if err := tx.QueryRow(ctx, query, id).Scan(&conv.Seller, &conv.AdvertisementTitle, &status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[SendMessage|exec get adv]: %w", ErrAdvertisementNotFound)
+		}
+		return nil, fmt.Errorf("[SendMessage|exec get adv]: %w", err)
+	}
+
+	conv.Buyer = login
+	if conv.Seller == login {
+		if req.Buyer == "" || req.Buyer == login {
+			return nil, fmt.Errorf("[SendMessage|check seller]: %w", ErrMessageOwnAdvertisement)
+		}
+		conv.Buyer = req.Buyer
+	}
+
+	now := time.Now()
+	query = "UPDATE conversations SET updated_at = $1 WHERE advertisement_id = $2 AND buyer = $3 RETURNING id,created_at"
+	err = tx.QueryRow(ctx, query, now, id, conv.Buyer).Scan(&conv.ID, &conv.CreatedAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// начать переписку может только покупатель и только по опубликованному объявлению
+		if conv.Seller == login {
+			return nil, fmt.Errorf("[SendMessage|exec get conversation]: %w", ErrConversationNotFound)
+		}
+		if !advertisements.IsPublicStatus(status) {
+			return nil, fmt.Errorf("[SendMessage|check status]: %w", ErrAdvertisementNotFound)
+		}
+
+		// продавец мог отключить сообщения в профиле, начатые переписки при этом продолжаются
+		var methods []string
+		if err := tx.QueryRow(ctx, "SELECT contact_methods FROM users WHERE login = $1", conv.Seller).Scan(&methods); err != nil {
+			return nil, fmt.Errorf("[SendMessage|exec get contact methods]: %w", err)
+		}
+		if !users.HasContactMethod(methods, users.ContactMessages) {
+			return nil, fmt.Errorf("[SendMessage|check contact methods]: %w", ErrMessagesDisabled)
+		}
+
+		query = `INSERT INTO conversations (advertisement_id,buyer,seller,created_at,updated_at) VALUES ($1,$2,$3,$4,$4)
+				ON CONFLICT (advertisement_id, buyer) DO UPDATE SET updated_at = EXCLUDED.updated_at
+				RETURNING id,created_at`
+		if err := tx.QueryRow(ctx, query, id, conv.Buyer, conv.Seller, now).Scan(&conv.ID, &conv.CreatedAt); err != nil {
+			return nil, fmt.Errorf("[SendMessage|exec create conversation]: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("[SendMessage|exec get conversation]: %w", err)
+	}
+	conv.UpdatedAt = now
+
+	msg := &messages.Message{
+		ConversationID: conv.ID,
+		Sender:         login,
+		Text:           req.Text,
+		CreatedAt:      now,
+	}
+
+	query = "INSERT INTO messages (conversation_id,sender,text,created_at) VALUES ($1,$2,$3,$4) RETURNING id"
+	if err := tx.QueryRow(ctx, query, conv.ID, login, req.Text, now).Scan(&msg.ID); err != nil {
+		return nil, fmt.Errorf("[SendMessage|exec create message]: %w", err)
+	}
+	conv.LastMessage = msg
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[SendMessage|commit tx]: %w", err)
+	}
+	return conv, nil
+}
+
+// GetConversations возвращает страницу переписок пользователя (как покупателя и как продавца)
+// с превью последнего сообщения, первыми идут переписки с самыми свежими сообщениями
+func GetConversations(ctx context.Context, login string, page, limit int) (*messages.ConversationPage, error) {
+	resp := &messages.ConversationPage{
+		Items: []*messages.Conversation{},
+		Page:  page,
+		Limit: limit,
+	}
+
+	batch := &pgx.Batch{}
+	query := `SELECT COUNT(*),
+				(SELECT COUNT(*) FROM messages m JOIN conversations c ON c.id = m.conversation_id
+				WHERE (c.buyer = $1 OR c.seller = $1) AND m.sender <> $1 AND m.read_at IS NULL)
+			FROM conversations
+			WHERE buyer = $1 OR seller = $1`
+	batch.Queue(query, login).QueryRow(func(row pgx.Row) error {
+		return row.Scan(&resp.Total, &resp.Unread)
+	})
+
+	query = `SELECT c.id,c.advertisement_id,a.title,c.buyer,c.seller,c.created_at,c.updated_at,
+				m.id,m.sender,m.text,m.created_at,m.read_at,
+				(SELECT COUNT(*) FROM messages u WHERE u.conversation_id = c.id AND u.sender <> $1 AND u.read_at IS NULL)
+			FROM conversations c
+			JOIN advertisements a ON a.id = c.advertisement_id
+			JOIN LATERAL (
+				SELECT id,sender,text,created_at,read_at FROM messages
+				WHERE conversation_id = c.id
+				ORDER BY id DESC
+				LIMIT 1
+			) m ON TRUE
+			WHERE c.buyer = $1 OR c.seller = $1
+			ORDER BY c.updated_at DESC, c.id DESC
+			LIMIT $2 OFFSET $3`
+
+	batch.Queue(query, login, limit, (page-1)*limit).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			conv := &messages.Conversation{LastMessage: &messages.Message{}}
+			if err := rows.Scan(&conv.ID, &conv.AdvertisementID, &conv.AdvertisementTitle, &conv.Buyer, &conv.Seller, &conv.CreatedAt, &conv.UpdatedAt,
+				&conv.LastMessage.ID, &conv.LastMessage.Sender, &conv.LastMessage.Text, &conv.LastMessage.CreatedAt, &conv.LastMessage.ReadAt,
+				&conv.Unread); err != nil {
+				return fmt.Errorf("[GetConversations|exec get conversation] %w", err)
+			}
+			conv.LastMessage.ConversationID = conv.ID
+			conv.LastMessage.Text = messages.Preview(conv.LastMessage.Text)
+			resp.Items = append(resp.Items, conv)
+		}
+		return rows.Err()
+	})
+
+	if err := Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("[GetConversations|exec get conversations] %w", err)
+	}
+
+	resp.HasNext = page*limit < resp.Total
+	return resp, nil
+}
+
+// GetMessages возвращает страницу сообщений переписки id, новые сообщения идут первыми. Читать переписку
+// могут только ее участники, входящие сообщения при этом отмечаются прочитанными
+func GetMessages(ctx context.Context, login string, id, page, limit int) (*messages.MessagePage, error) {
+	if _, err := GetConversationPeer(ctx, login, id); err != nil {
+		return nil, fmt.Errorf("[GetMessages] %w", err)
+	}
+
+	query := "UPDATE messages SET read_at = $1 WHERE conversation_id = $2 AND sender <> $3 AND read_at IS NULL"
+	if _, err := Pool.Exec(ctx, query, time.Now(), id, login); err != nil {
+		return nil, fmt.Errorf("[GetMessages|exec mark read]: %w", err)
+	}
+
+	resp := &messages.MessagePage{
+		Items: []*messages.Message{},
+		Page:  page,
+		Limit: limit,
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("SELECT COUNT(*) FROM messages WHERE conversation_id = $1", id).QueryRow(func(row pgx.Row) error {
+		return row.Scan(&resp.Total)
+	})
+
+	query = `SELECT id,sender,text,created_at,read_at
+			FROM messages
+			WHERE conversation_id = $1
+			ORDER BY id DESC
+			LIMIT $2 OFFSET $3`
+
+	batch.Queue(query, id, limit, (page-1)*limit).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			msg := &messages.Message{ConversationID: id}
+			if err := rows.Scan(&msg.ID, &msg.Sender, &msg.Text, &msg.CreatedAt, &msg.ReadAt); err != nil {
+				return fmt.Errorf("[GetMessages|exec get message] %w", err)
+			}
+			resp.Items = append(resp.Items, msg)
+		}
+		return rows.Err()
+	})
+
+	if err := Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("[GetMessages|exec get messages] %w", err)
+	}
+
+	resp.HasNext = page*limit < resp.Total
+	return resp, nil
+}
+
+// GetConversationPeer возвращает собеседника пользователя в переписке id, если пользователь ее участник
+func GetConversationPeer(ctx context.Context, login string, id int) (string, error) {
+	var buyer, seller string
+	query := "SELECT buyer,seller FROM conversations WHERE id = $1"
+	if err := Pool.QueryRow(ctx, query, id).Scan(&buyer, &seller); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("[GetConversationPeer|exec get conversation]: %w", ErrConversationNotFound)
+		}
+		return "", fmt.Errorf("[GetConversationPeer|exec get conversation]: %w", err)
+	}
+
+	switch login {
+	case buyer:
+		return seller, nil
+	case seller:
+		return buyer, nil
+	default:
+		return "", fmt.Errorf("[GetConversationPeer|check participant]: %w", ErrNotConversationParticipant)
+	}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vk_intern/internal/advertisements"
+)
+
+// GetPendingAdvertisements возвращает страницу очереди модерации, первыми идут самые старые объявления
+func GetPendingAdvertisements(ctx context.Context, params *advertisements.AdvertisementFilter) (*advertisements.AdvertisementPage, error) {
+	page := &advertisements.AdvertisementPage{
+		Items: []*advertisements.AdvertisementResponse{},
+		Page:  params.Page,
+		Limit: params.Limit,
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("SELECT COUNT(*) FROM advertisements WHERE status = $1", advertisements.StatusPending).QueryRow(func(row pgx.Row) error {
+		return row.Scan(&page.Total)
+	})
+
+	query := `SELECT id,title,description,price,image_url,login,status,COALESCE(category_id, 0),created_at 
+			FROM advertisements 
+			WHERE status = $1
+			ORDER BY created_at ASC, id ASC
+			LIMIT $2 OFFSET $3`
+
+	offset := (params.Page - 1) * params.Limit
+	batch.Queue(query, advertisements.StatusPending, params.Limit, offset).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			var curAdv advertisements.AdvertisementResponse
+			err := rows.Scan(&curAdv.ID, &curAdv.Title, &curAdv.Description, &curAdv.Price, &curAdv.ImageURL, &curAdv.UserLogin, &curAdv.Status, &curAdv.CategoryID, &curAdv.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("[GetPendingAdvertisements|exec get adv] %w", err)
+			}
+			curAdv.Thumbnails = advertisements.ThumbnailURLs(curAdv.ImageURL)
+
+			page.Items = append(page.Items, &curAdv)
+		}
+		return rows.Err()
+	})
+
+	if err := Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("[GetPendingAdvertisements|exec get advs] %w", err)
+	}
+
+	page.HasNext = params.Page*params.Limit < page.Total
+	return page, nil
+}
+
+// ModerateAdvertisement одобряет (active) или отклоняет (rejected) объявление из очереди модерации
+// или скрытое по жалобам
+func ModerateAdvertisement(ctx context.Context, moderator string, id int, status, reason string) (*advertisements.Advertisement, error) {
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[ModerateAdvertisement|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var curStatus string
+	query := "SELECT status FROM advertisements WHERE id = $1 FOR UPDATE"
+	if err := tx.QueryRow(ctx, query, id).Scan(&curStatus); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[ModerateAdvertisement|exec get status]: %w", ErrAdvertisementNotFound)
+		}
+		return nil, fmt.Errorf("[ModerateAdvertisement|exec get status]: %w", err)
+	}
+
+	// проверим, что модератор может принять такое решение по объявлению в текущем статусе
+	if err := advertisements.ValidateModerationTransition(curStatus, status); err != nil {
+		return nil, fmt.Errorf("[ModerateAdvertisement|check transition]: %w", err)
+	}
+
+	// решение модератора закрывает все жалобы на объявление
+	if err := resolveAdvertisementReports(ctx, tx, id); err != nil {
+		return nil, fmt.Errorf("[ModerateAdvertisement|resolve reports]: %w", err)
+	}
+
+	query = `UPDATE advertisements SET status = $1, moderation_reason = NULLIF($2, ''), moderated_by = $3, moderated_at = $4
+			WHERE id = $5
+			RETURNING id,title,description,price,image_url,login,status,COALESCE(category_id, 0),created_at,COALESCE(moderation_reason, '')`
+
+	var adv advertisements.Advertisement
+	err = tx.QueryRow(ctx, query, status, reason, moderator, time.Now(), id).Scan(&adv.ID, &adv.Title, &adv.Description, &adv.Price, &adv.ImageURL, &adv.UserLogin, &adv.Status, &adv.CategoryID, &adv.CreatedAt, &adv.ModerationReason)
+	if err != nil {
+		return nil, fmt.Errorf("[ModerateAdvertisement|exec update status]: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[ModerateAdvertisement|commit tx]: %w", err)
+	}
+	return &adv, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vk_intern/internal/notifications"
+)
+
+func CreateNotification(ctx context.Context, login, typ string, payload []byte) (*notifications.Notification, error) {
+	n := &notifications.Notification{
+		Login:     login,
+		Type:      typ,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}
+
+	query := "INSERT INTO notifications (login,type,payload,created_at) VALUES ($1,$2,$3,$4) RETURNING id"
+	if err := Pool.QueryRow(ctx, query, login, typ, payload, n.CreatedAt).Scan(&n.ID); err != nil {
+		return nil, fmt.Errorf("[CreateNotification|exec create notification]: %w", err)
+	}
+	return n, nil
+}
+
+// CreateFavoritesNotifications кладет уведомление всем пользователям, добавившим объявление в избранное,
+// кроме его автора, и возвращает созданные уведомления
+func CreateFavoritesNotifications(ctx context.Context, id int, typ string, payload []byte) ([]*notifications.Notification, error) {
+	now := time.Now()
+	query := `INSERT INTO notifications (login,type,payload,created_at)
+			SELECT f.login, $2, $3, $4 
+			FROM favorites f
+			JOIN advertisements a ON a.id = f.advertisement_id
+			WHERE f.advertisement_id = $1 AND f.login <> a.login
+			RETURNING id,login`
+	rows, err := Pool.Query(ctx, query, id, typ, payload, now)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateFavoritesNotifications|exec create notifications]: %w", err)
+	}
+	defer rows.Close()
+
+	created := []*notifications.Notification{}
+	for rows.Next() {
+		n := &notifications.Notification{Type: typ, Payload: payload, CreatedAt: now}
+		if err := rows.Scan(&n.ID, &n.Login); err != nil {
+			return nil, fmt.Errorf("[CreateFavoritesNotifications|exec create notification]: %w", err)
+		}
+		created = append(created, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[CreateFavoritesNotifications|exec create notifications]: %w", err)
+	}
+	return created, nil
+}
+
+// GetNotifications возвращает страницу уведомлений пользователя, новые уведомления идут первыми
+func GetNotifications(ctx context.Context, login string, page, limit int) (*notifications.NotificationPage, error) {
+	resp := &notifications.NotificationPage{
+		Items: []*notifications.Notification{},
+		Page:  page,
+		Limit: limit,
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("SELECT COUNT(*), COUNT(*) FILTER (WHERE read_at IS NULL) FROM notifications WHERE login = $1", login).QueryRow(func(row pgx.Row) error {
+		return row.Scan(&resp.Total, &resp.Unread)
+	})
+
+	query := `SELECT id,type,payload,created_at,read_at 
+			FROM notifications 
+			WHERE login = $1
+			ORDER BY id DESC
+			LIMIT $2 OFFSET $3`
+
+	batch.Queue(query, login, limit, (page-1)*limit).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			var n notifications.Notification
+			if err := rows.Scan(&n.ID, &n.Type, &n.Payload, &n.CreatedAt, &n.ReadAt); err != nil {
+				return fmt.Errorf("[GetNotifications|exec get notification] %w", err)
+			}
+			resp.Items = append(resp.Items, &n)
+		}
+		return rows.Err()
+	})
+
+	if err := Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("[GetNotifications|exec get notifications] %w", err)
+	}
+
+	resp.HasNext = page*limit < resp.Total
+	return resp, nil
+}
+
+// MarkNotificationsRead отмечает прочитанными уведомления пользователя с идентификаторами ids
+// (все уведомления, если ids пустой) и возвращает количество оставшихся непрочитанных
+func MarkNotificationsRead(ctx context.Context, login string, ids []int) (int, error) {
+	query := "UPDATE notifications SET read_at = $1 WHERE login = $2 AND read_at IS NULL"
+	args := []interface{}{time.Now(), login}
+	if len(ids) > 0 {
+		args = append(args, ids)
+		query += " AND id = ANY($3)"
+	}
+
+	if _, err := Pool.Exec(ctx, query, args...); err != nil {
+		return 0, fmt.Errorf("[MarkNotificationsRead|exec mark read]: %w", err)
+	}
+
+	var unread int
+	query = "SELECT COUNT(*) FROM notifications WHERE login = $1 AND read_at IS NULL"
+	if err := Pool.QueryRow(ctx, query, login).Scan(&unread); err != nil {
+		return 0, fmt.Errorf("[MarkNotificationsRead|exec count unread]: %w", err)
+	}
+	return unread, nil
+}
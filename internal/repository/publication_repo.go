@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vk_intern/internal/advertisements"
+)
+
+var publicationColumns = `p.id,a.id,a.title,a.description,a.price,a.image_url,a.login,a.status,COALESCE(a.category_id, 0),a.created_at,` +
+	fmt.Sprintf(sellerRatingExpr, "a.login")
+
+// CreatePublication записывает публикацию объявления id в поток свежих объявлений. В поток попадает
+// только первая публикация: объявление, которое снова опубликовали (например, сняли с брони),
+// свежим не считается. Возвращает, была ли записана публикация
+func CreatePublication(ctx context.Context, id int) (bool, error) {
+	query := "INSERT INTO advertisement_publications (advertisement_id,published_at) VALUES ($1,$2) ON CONFLICT (advertisement_id) DO NOTHING"
+	tag, err := Pool.Exec(ctx, query, id, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("[CreatePublication|exec create publication]: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetLastPublicationID возвращает номер последней публикации или 0, если публикаций еще не было
+func GetLastPublicationID(ctx context.Context) (int64, error) {
+	var id int64
+	query := "SELECT COALESCE(MAX(id), 0) FROM advertisement_publications"
+	if err := Pool.QueryRow(ctx, query).Scan(&id); err != nil {
+		return 0, fmt.Errorf("[GetLastPublicationID|exec get last id]: %w", err)
+	}
+	return id, nil
+}
+
+// GetPublications возвращает до limit публикаций после события after, подходящих под цены и категорию
+// фильтра. Объявления, которые с тех пор сняли с публикации, пропускаются
+func GetPublications(ctx context.Context, after int64, params *advertisements.AdvertisementFilter, limit int) ([]*advertisements.Publication, error) {
+	query := `SELECT ` + publicationColumns + `
+			FROM advertisement_publications p
+			JOIN advertisements a ON a.id = p.advertisement_id
+			WHERE p.id > $1 AND a.price BETWEEN $2 AND $3 AND a.status = ANY($4)`
+	args := []interface{}{after, params.MinPrice, params.MaxPrice, advertisements.PublicStatuses}
+
+	if params.Category != "" {
+		args = append(args, params.Category)
+		query += " AND a.category_id IN " + fmt.Sprintf(categorySubtreeQuery, len(args))
+	}
+
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY p.id LIMIT $%d", len(args))
+
+	rows, err := Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[GetPublications|exec get publications]: %w", err)
+	}
+	defer rows.Close()
+
+	pubs := []*advertisements.Publication{}
+	for rows.Next() {
+		pub, err := scanPublication(rows)
+		if err != nil {
+			return nil, fmt.Errorf("[GetPublications|exec get publication]: %w", err)
+		}
+		pubs = append(pubs, pub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetPublications|exec get publications]: %w", err)
+	}
+	return pubs, nil
+}
+
+func scanPublication(row pgx.Row) (*advertisements.Publication, error) {
+	pub := &advertisements.Publication{Advertisement: &advertisements.AdvertisementResponse{}}
+	adv := pub.Advertisement
+	if err := row.Scan(&pub.EventID, &adv.ID, &adv.Title, &adv.Description, &adv.Price, &adv.ImageURL, &adv.UserLogin, &adv.Status, &adv.CategoryID, &adv.CreatedAt, &adv.SellerRating); err != nil {
+		return nil, err
+	}
+	adv.Thumbnails = advertisements.ThumbnailURLs(adv.ImageURL)
+	return pub, nil
+}
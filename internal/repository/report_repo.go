@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/reports"
+)
+
+var (
+	ErrReportExists           = errors.New("user has already reported this advertisement")
+	ErrReportOwnAdvertisement = errors.New("user can not report own advertisement")
+)
+
+// CreateReport сохраняет жалобу на объявление. Если необработанных жалоб от разных пользователей
+// набралось hideThreshold, объявление в статусе active скрывается до решения модератора (0 отключает скрытие).
+// Забронированные и проданные объявления не скрываются: одобрение вернуло бы их в продажу как active,
+// жалобы на них остаются в списке модератора
+func CreateReport(ctx context.Context, login string, id int, req *reports.CreateReportRequest, hideThreshold int) (*reports.Report, error) {
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateReport|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// блокируем объявление, чтобы параллельные жалобы не разошлись в подсчете
+	var owner, status string
+	query := "SELECT login,status FROM advertisements WHERE id = $1 FOR UPDATE"
+	if err := tx.QueryRow(ctx, query, id).Scan(&owner, &status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[CreateReport|exec get adv]: %w", ErrAdvertisementNotFound)
+		}
+		return nil, fmt.Errorf("[CreateReport|exec get adv]: %w", err)
+	}
+
+	if owner == login {
+		return nil, fmt.Errorf("[CreateReport|check owner]: %w", ErrReportOwnAdvertisement)
+	}
+	// на непубличные объявления жаловаться нельзя, для остальных пользователей их не существует
+	if !advertisements.IsPublicStatus(status) {
+		return nil, fmt.Errorf("[CreateReport|check status]: %w", ErrAdvertisementNotFound)
+	}
+
+	report := &reports.Report{
+		AdvertisementID: id,
+		Login:           login,
+		Reason:          req.Reason,
+		Comment:         req.Comment,
+		CreatedAt:       time.Now(),
+	}
+
+	query = `INSERT INTO advertisement_reports (advertisement_id,login,reason,comment,created_at) VALUES ($1,$2,$3,$4,$5)
+			ON CONFLICT (advertisement_id, login) WHERE resolved_at IS NULL DO NOTHING
+			RETURNING id`
+	if err := tx.QueryRow(ctx, query, id, login, req.Reason, req.Comment, report.CreatedAt).Scan(&report.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[CreateReport|exec create report]: %w", ErrReportExists)
+		}
+		return nil, fmt.Errorf("[CreateReport|exec create report]: %w", err)
+	}
+
+	if hideThreshold > 0 {
+		var count int
+		query = "SELECT COUNT(*) FROM advertisement_reports WHERE advertisement_id = $1 AND resolved_at IS NULL"
+		if err := tx.QueryRow(ctx, query, id).Scan(&count); err != nil {
+			return nil, fmt.Errorf("[CreateReport|exec count reports]: %w", err)
+		}
+
+		if count >= hideThreshold && status == advertisements.StatusActive {
+			query = "UPDATE advertisements SET status = $1 WHERE id = $2"
+			if _, err := tx.Exec(ctx, query, advertisements.StatusHidden, id); err != nil {
+				return nil, fmt.Errorf("[CreateReport|exec hide adv]: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[CreateReport|commit tx]: %w", err)
+	}
+	return report, nil
+}
+
+// GetReportedAdvertisements возвращает страницу объявлений с необработанными жалобами,
+// первыми идут объявления с наибольшим количеством жалоб
+func GetReportedAdvertisements(ctx context.Context, params *advertisements.AdvertisementFilter) (*reports.ReportedAdvertisementPage, error) {
+	page := &reports.ReportedAdvertisementPage{
+		Items: []*reports.ReportedAdvertisement{},
+		Page:  params.Page,
+		Limit: params.Limit,
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("SELECT COUNT(DISTINCT advertisement_id) FROM advertisement_reports WHERE resolved_at IS NULL").QueryRow(func(row pgx.Row) error {
+		return row.Scan(&page.Total)
+	})
+
+	query := `SELECT a.id,a.title,a.login,a.status,COUNT(*),MAX(r.created_at)
+			FROM advertisement_reports r
+			JOIN advertisements a ON a.id = r.advertisement_id
+			WHERE r.resolved_at IS NULL
+			GROUP BY a.id
+			ORDER BY COUNT(*) DESC, MAX(r.created_at) DESC, a.id DESC
+			LIMIT $1 OFFSET $2`
+
+	byID := map[int]*reports.ReportedAdvertisement{}
+	offset := (params.Page - 1) * params.Limit
+	batch.Queue(query, params.Limit, offset).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			item := &reports.ReportedAdvertisement{Reports: []*reports.Report{}}
+			if err := rows.Scan(&item.AdvertisementID, &item.Title, &item.UserLogin, &item.Status, &item.ReportsCount, &item.LastReportedAt); err != nil {
+				return fmt.Errorf("[GetReportedAdvertisements|exec get adv] %w", err)
+			}
+			page.Items = append(page.Items, item)
+			byID[item.AdvertisementID] = item
+		}
+		return rows.Err()
+	})
+
+	if err := Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("[GetReportedAdvertisements|exec get advs] %w", err)
+	}
+	page.HasNext = params.Page*params.Limit < page.Total
+
+	if len(page.Items) == 0 {
+		return page, nil
+	}
+
+	// жалобы на все объявления страницы получаем одним запросом
+	ids := make([]int, 0, len(page.Items))
+	for _, item := range page.Items {
+		ids = append(ids, item.AdvertisementID)
+	}
+
+	query = `SELECT id,advertisement_id,login,reason,comment,created_at
+			FROM advertisement_reports
+			WHERE advertisement_id = ANY($1) AND resolved_at IS NULL
+			ORDER BY created_at DESC, id DESC`
+	rows, err := Pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("[GetReportedAdvertisements|exec get reports] %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var report reports.Report
+		if err := rows.Scan(&report.ID, &report.AdvertisementID, &report.Login, &report.Reason, &report.Comment, &report.CreatedAt); err != nil {
+			return nil, fmt.Errorf("[GetReportedAdvertisements|exec get report] %w", err)
+		}
+		byID[report.AdvertisementID].Reports = append(byID[report.AdvertisementID].Reports, &report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetReportedAdvertisements|exec get reports] %w", err)
+	}
+	return page, nil
+}
+
+// DismissReports закрывает жалобы на объявление без изменения его статуса. Скрытое объявление
+// так остаться без жалоб не может, его модератор одобряет или отклоняет
+func DismissReports(ctx context.Context, id int) error {
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[DismissReports|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	query := "SELECT status FROM advertisements WHERE id = $1 FOR UPDATE"
+	if err := tx.QueryRow(ctx, query, id).Scan(&status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("[DismissReports|exec get status]: %w", ErrAdvertisementNotFound)
+		}
+		return fmt.Errorf("[DismissReports|exec get status]: %w", err)
+	}
+	if status == advertisements.StatusHidden {
+		return fmt.Errorf("[DismissReports|check status]: %w", advertisements.ErrWrongStatusTransition)
+	}
+
+	if err := resolveAdvertisementReports(ctx, tx, id); err != nil {
+		return fmt.Errorf("[DismissReports|resolve reports]: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[DismissReports|commit tx]: %w", err)
+	}
+	return nil
+}
+
+func resolveAdvertisementReports(ctx context.Context, tx pgx.Tx, id int) error {
+	query := "UPDATE advertisement_reports SET resolved_at = $1 WHERE advertisement_id = $2 AND resolved_at IS NULL"
+	if _, err := tx.Exec(ctx, query, time.Now(), id); err != nil {
+		return fmt.Errorf("[resolveAdvertisementReports|exec resolve]: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vk_intern/internal/reviews"
+)
+
+// sellerRatingExpr выражение для списка выбираемых полей объявления, средняя оценка его продавца
+// (NULL, пока отзывов нет), на место %s подставляется столбец с логином продавца
+const sellerRatingExpr = `(SELECT ROUND(AVG(r.rating), 2)::float8 FROM reviews r WHERE r.seller = %s)`
+
+var (
+	ErrReviewExists           = errors.New("user has already reviewed this advertisement")
+	ErrReviewOwnAdvertisement = errors.New("user can not review own advertisement")
+	ErrReviewNoConversation   = errors.New("seller has not replied to user about this advertisement")
+)
+
+// CreateReview сохраняет отзыв покупателя о продавце объявления id. Оставить отзыв может только
+// покупатель, которому продавец ответил в переписке по этому объявлению, один раз на объявление.
+// Одного сообщения от покупателя недостаточно, иначе отзыв можно оставить, просто написав продавцу
+func CreateReview(ctx context.Context, login string, id int, req *reviews.CreateReviewRequest) (*reviews.Review, error) {
+	review := &reviews.Review{
+		AdvertisementID: &id,
+		Author:          login,
+		Rating:          req.Rating,
+		Comment:         req.Comment,
+		CreatedAt:       time.Now(),
+	}
+
+	query := "SELECT login FROM advertisements WHERE id = $1"
+	if err := Pool.QueryRow(ctx, query, id).Scan(&review.Seller); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[CreateReview|exec get adv]: %w", ErrAdvertisementNotFound)
+		}
+		return nil, fmt.Errorf("[CreateReview|exec get adv]: %w", err)
+	}
+	if review.Seller == login {
+		return nil, fmt.Errorf("[CreateReview|check owner]: %w", ErrReviewOwnAdvertisement)
+	}
+
+	var hasConversation bool
+	query = `SELECT EXISTS(SELECT 1 FROM messages m JOIN conversations c ON c.id = m.conversation_id
+				WHERE c.advertisement_id = $1 AND c.buyer = $2 AND m.sender = c.seller)`
+	if err := Pool.QueryRow(ctx, query, id, login).Scan(&hasConversation); err != nil {
+		return nil, fmt.Errorf("[CreateReview|exec check conversation]: %w", err)
+	}
+	if !hasConversation {
+		return nil, fmt.Errorf("[CreateReview|check conversation]: %w", ErrReviewNoConversation)
+	}
+
+	query = `INSERT INTO reviews (advertisement_id,seller,author,rating,comment,created_at) VALUES ($1,$2,$3,$4,$5,$6)
+			ON CONFLICT (advertisement_id, author) DO NOTHING
+			RETURNING id`
+	err := Pool.QueryRow(ctx, query, id, review.Seller, login, req.Rating, req.Comment, review.CreatedAt).Scan(&review.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[CreateReview|exec create review]: %w", ErrReviewExists)
+		}
+		return nil, fmt.Errorf("[CreateReview|exec create review]: %w", err)
+	}
+	return review, nil
+}
+
+// GetReviews возвращает страницу отзывов о продавце seller, новые отзывы идут первыми,
+// вместе со средней оценкой и количеством всех отзывов
+func GetReviews(ctx context.Context, seller string, page, limit int) (*reviews.ReviewPage, error) {
+	exists, err := checkLoginExists(ctx, seller)
+	if err != nil {
+		return nil, fmt.Errorf("[GetReviews] %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("[GetReviews|check exists]: %w", ErrUserLoginWrong)
+	}
+
+	resp := &reviews.ReviewPage{
+		Items: []*reviews.Review{},
+		Page:  page,
+		Limit: limit,
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("SELECT COUNT(*), ROUND(AVG(rating), 2)::float8 FROM reviews WHERE seller = $1", seller).QueryRow(func(row pgx.Row) error {
+		return row.Scan(&resp.Total, &resp.Rating)
+	})
+
+	query := `SELECT id,advertisement_id,seller,author,rating,comment,created_at
+			FROM reviews
+			WHERE seller = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2 OFFSET $3`
+
+	batch.Queue(query, seller, limit, (page-1)*limit).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			var r reviews.Review
+			if err := rows.Scan(&r.ID, &r.AdvertisementID, &r.Seller, &r.Author, &r.Rating, &r.Comment, &r.CreatedAt); err != nil {
+				return fmt.Errorf("[GetReviews|exec get review] %w", err)
+			}
+			resp.Items = append(resp.Items, &r)
+		}
+		return rows.Err()
+	})
+
+	if err := Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("[GetReviews|exec get reviews] %w", err)
+	}
+
+	resp.HasNext = page*limit < resp.Total
+	return resp, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RevokeJTI заносит access токен в список отозванных до истечения его срока действия
+func RevokeJTI(ctx context.Context, jti, login string, expiresAt time.Time) error {
+	query := "INSERT INTO revoked_tokens (jti,login,expires_at,revoked_at) VALUES ($1,$2,$3,$4) ON CONFLICT (jti) DO NOTHING"
+	if _, err := Pool.Exec(ctx, query, jti, login, expiresAt, time.Now()); err != nil {
+		return fmt.Errorf("[RevokeJTI|exec revoke]: %w", err)
+	}
+	return nil
+}
+
+func CheckJTIRevoked(ctx context.Context, jti string) (bool, error) {
+	var exists bool
+	query := "SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)"
+	if err := Pool.QueryRow(ctx, query, jti).Scan(&exists); err != nil {
+		return false, fmt.Errorf("[CheckJTIRevoked|exec check]: %w", err)
+	}
+	return exists, nil
+}
+
+// RevokeAllUserTokens отзывает все выданные пользователю токены: access токены, выпущенные
+// раньше at, и все refresh токены. Время выпуска токена хранится с точностью до секунды,
+// поэтому at тоже округляется вниз до секунды
+func RevokeAllUserTokens(ctx context.Context, login string, at time.Time) error {
+	at = at.Truncate(time.Second)
+
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[RevokeAllUserTokens|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := "UPDATE users SET tokens_revoked_at = $1 WHERE login = $2"
+	if _, err := tx.Exec(ctx, query, at, login); err != nil {
+		return fmt.Errorf("[RevokeAllUserTokens|exec revoke access]: %w", err)
+	}
+
+	query = "UPDATE refresh_tokens SET revoked_at = $1 WHERE login = $2 AND revoked_at IS NULL"
+	if _, err := tx.Exec(ctx, query, at, login); err != nil {
+		return fmt.Errorf("[RevokeAllUserTokens|exec revoke refresh]: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[RevokeAllUserTokens|commit tx]: %w", err)
+	}
+	return nil
+}
+
+// CheckUserTokenRevoked проверяет, отозван ли access токен пользователя, выпущенный в момент iat:
+// отозваны токены, выпущенные раньше tokens_revoked_at. Вместе с результатом возвращает сам
+// момент отзыва или nil, если такого отзыва не было
+func CheckUserTokenRevoked(ctx context.Context, login string, iat time.Time) (bool, *time.Time, error) {
+	var (
+		revoked   bool
+		revokedAt *time.Time
+	)
+	query := "SELECT tokens_revoked_at IS NOT NULL AND $2 < tokens_revoked_at, tokens_revoked_at FROM users WHERE login = $1"
+	if err := Pool.QueryRow(ctx, query, login, iat).Scan(&revoked, &revokedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil, fmt.Errorf("[CheckUserTokenRevoked|exec check]: %w", ErrUserLoginWrong)
+		}
+		return false, nil, fmt.Errorf("[CheckUserTokenRevoked|exec check]: %w", err)
+	}
+	return revoked, revokedAt, nil
+}
+
+// RevokeRefreshTokenFamily отзывает цепочку refresh токенов пользователя login, в которую входит токен hash
+func RevokeRefreshTokenFamily(ctx context.Context, login, hash string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 
+			WHERE revoked_at IS NULL AND family = (SELECT family FROM refresh_tokens WHERE token_hash = $2 AND login = $3)`
+	if _, err := Pool.Exec(ctx, query, time.Now(), hash, login); err != nil {
+		return fmt.Errorf("[RevokeRefreshTokenFamily|exec revoke]: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredRevokedTokens удаляет из списка отозванных токены, срок действия которых уже истек
+func DeleteExpiredRevokedTokens(ctx context.Context) error {
+	query := "DELETE FROM revoked_tokens WHERE expires_at < $1"
+	if _, err := Pool.Exec(ctx, query, time.Now()); err != nil {
+		return fmt.Errorf("[DeleteExpiredRevokedTokens|exec delete]: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/searches"
+)
+
+var (
+	ErrSavedSearchNotFound = errors.New("saved search does not exists")
+)
+
+func CreateSavedSearch(ctx context.Context, login, name string, filter *advertisements.AdvertisementFilter) (*searches.SavedSearch, error) {
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSavedSearch|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// блокируем пользователя, чтобы параллельные запросы не превысили лимит поисков
+	query := "SELECT 1 FROM users WHERE login = $1 FOR UPDATE"
+	if _, err := tx.Exec(ctx, query, login); err != nil {
+		return nil, fmt.Errorf("[CreateSavedSearch|exec lock user]: %w", err)
+	}
+
+	var count int
+	query = "SELECT COUNT(*) FROM saved_searches WHERE login = $1"
+	if err := tx.QueryRow(ctx, query, login).Scan(&count); err != nil {
+		return nil, fmt.Errorf("[CreateSavedSearch|exec count]: %w", err)
+	}
+	if count >= searches.MaxSavedSearches {
+		return nil, fmt.Errorf("[CreateSavedSearch|check count]: %w", searches.ErrTooManySavedSearches)
+	}
+
+	search := &searches.SavedSearch{
+		Name:      name,
+		MinPrice:  filter.MinPrice,
+		MaxPrice:  filter.MaxPrice,
+		Query:     filter.Query,
+		Category:  filter.Category,
+		CreatedAt: time.Now(),
+	}
+
+	query = "INSERT INTO saved_searches (login,name,min_price,max_price,query,category,created_at) VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id"
+	err = tx.QueryRow(ctx, query, login, search.Name, search.MinPrice, search.MaxPrice, search.Query, search.Category, search.CreatedAt).Scan(&search.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSavedSearch|exec create search]: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[CreateSavedSearch|commit tx]: %w", err)
+	}
+	return search, nil
+}
+
+func GetSavedSearches(ctx context.Context, login string) ([]*searches.SavedSearch, error) {
+	result := []*searches.SavedSearch{}
+
+	query := "SELECT id,name,min_price,max_price,query,category,created_at FROM saved_searches WHERE login = $1 ORDER BY id"
+	rows, err := Pool.Query(ctx, query, login)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSavedSearches|exec get searches] %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s searches.SavedSearch
+		if err := rows.Scan(&s.ID, &s.Name, &s.MinPrice, &s.MaxPrice, &s.Query, &s.Category, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("[GetSavedSearches|exec get search] %w", err)
+		}
+		result = append(result, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetSavedSearches|exec get searches] %w", err)
+	}
+	return result, nil
+}
+
+func DeleteSavedSearch(ctx context.Context, login string, id int) error {
+	query := "DELETE FROM saved_searches WHERE id = $1 AND login = $2"
+	tag, err := Pool.Exec(ctx, query, id, login)
+	if err != nil {
+		return fmt.Errorf("[DeleteSavedSearch|exec delete search]: %w", err)
+	}
+
+	// чужой поиск для пользователя не существует
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("[DeleteSavedSearch|exec delete search]: %w", ErrSavedSearchNotFound)
+	}
+	return nil
+}
+
+// GetSavedSearchMatches возвращает страницу объявлений, подошедших под сохраненные поиски пользователя,
+// новые совпадения идут первыми. Объявления, которые с тех пор сняли с публикации, пропускаются
+func GetSavedSearchMatches(ctx context.Context, login string, page, limit int) (*searches.MatchedAdvertisementPage, error) {
+	resp := &searches.MatchedAdvertisementPage{
+		Items: []*searches.MatchedAdvertisement{},
+		Page:  page,
+		Limit: limit,
+	}
+
+	from := `FROM saved_search_matches m
+			JOIN saved_searches s ON s.id = m.saved_search_id
+			JOIN advertisements a ON a.id = m.advertisement_id
+			WHERE s.login = $1 AND a.status = ANY($2)`
+
+	batch := &pgx.Batch{}
+	batch.Queue("SELECT COUNT(*) "+from, login, advertisements.PublicStatuses).QueryRow(func(row pgx.Row) error {
+		return row.Scan(&resp.Total)
+	})
+
+	query := `SELECT s.id,s.name,a.id,a.title,a.price,a.image_url,m.created_at 
+			` + from + `
+			ORDER BY m.created_at DESC, a.id DESC
+			LIMIT $3 OFFSET $4`
+
+	batch.Queue(query, login, advertisements.PublicStatuses, limit, (page-1)*limit).Query(func(rows pgx.Rows) error {
+		for rows.Next() {
+			var m searches.MatchedAdvertisement
+			if err := rows.Scan(&m.SavedSearchID, &m.SavedSearchName, &m.AdvertisementID, &m.Title, &m.Price, &m.ImageURL, &m.MatchedAt); err != nil {
+				return fmt.Errorf("[GetSavedSearchMatches|exec get match] %w", err)
+			}
+			resp.Items = append(resp.Items, &m)
+		}
+		return rows.Err()
+	})
+
+	if err := Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("[GetSavedSearchMatches|exec get matches] %w", err)
+	}
+
+	resp.HasNext = page*limit < resp.Total
+	return resp, nil
+}
+
+// MatchSavedSearches находит чужие сохраненные поиски, под которые подходит опубликованное объявление,
+// и запоминает совпадения. Возвращаются только новые совпадения, поэтому объявление попадает
+// в совпадения каждого поиска один раз, даже если его публиковали повторно
+func MatchSavedSearches(ctx context.Context, id int) ([]*searches.SavedSearchMatch, error) {
+	// категория поиска подходит, если это категория объявления или любой из ее предков
+	query := `WITH RECURSIVE ancestors AS (
+				SELECT c.id, c.parent_id, c.slug FROM categories c JOIN advertisements a ON a.category_id = c.id WHERE a.id = $1
+				UNION ALL
+				SELECT c.id, c.parent_id, c.slug FROM categories c JOIN ancestors an ON c.id = an.parent_id
+			), matched AS (
+				INSERT INTO saved_search_matches (saved_search_id,advertisement_id,created_at)
+				SELECT s.id, a.id, $2 
+				FROM saved_searches s 
+				JOIN advertisements a ON a.id = $1
+				WHERE a.status = $3 AND s.login <> a.login
+					AND a.price BETWEEN s.min_price AND s.max_price
+					AND (s.query = '' OR a.search_vector @@ (websearch_to_tsquery('russian', s.query) || websearch_to_tsquery('english', s.query)))
+					AND (s.category = '' OR s.category IN (SELECT slug FROM ancestors))
+				ON CONFLICT DO NOTHING
+				RETURNING saved_search_id
+			)
+			SELECT s.id,s.name,s.login,a.id,a.title,a.price 
+			FROM matched m 
+			JOIN saved_searches s ON s.id = m.saved_search_id
+			JOIN advertisements a ON a.id = $1`
+
+	rows, err := Pool.Query(ctx, query, id, time.Now(), advertisements.StatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("[MatchSavedSearches|exec match] %w", err)
+	}
+	defer rows.Close()
+
+	matches := []*searches.SavedSearchMatch{}
+	for rows.Next() {
+		var m searches.SavedSearchMatch
+		if err := rows.Scan(&m.SavedSearchID, &m.SavedSearchName, &m.Login, &m.AdvertisementID, &m.Title, &m.Price); err != nil {
+			return nil, fmt.Errorf("[MatchSavedSearches|exec get match] %w", err)
+		}
+		matches = append(matches, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[MatchSavedSearches|exec match] %w", err)
+	}
+	return matches, nil
+}
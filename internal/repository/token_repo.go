@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid")
+	ErrRefreshTokenExpired = errors.New("refresh token is expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, token family revoked")
+)
+
+func SaveRefreshToken(ctx context.Context, login, hash, family string, expiresAt time.Time) error {
+	query := "INSERT INTO refresh_tokens (login,token_hash,family,expires_at,created_at) VALUES ($1,$2,$3,$4,$5)"
+	if _, err := Pool.Exec(ctx, query, login, hash, family, expiresAt, time.Now()); err != nil {
+		return fmt.Errorf("[SaveRefreshToken|exec save token]: %w", err)
+	}
+	return nil
+}
+
+// RotateRefreshToken погашает refresh токен oldHash и выдает вместо него newHash в той же цепочке.
+// Повторное предъявление уже погашенного токена означает его утечку: вся цепочка отзывается.
+// Возвращает логин владельца токена
+func RotateRefreshToken(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (string, error) {
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("[RotateRefreshToken|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		login, family     string
+		tokenExpiresAt    time.Time
+		usedAt, revokedAt *time.Time
+	)
+	query := "SELECT login,family,expires_at,used_at,revoked_at FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE"
+	err = tx.QueryRow(ctx, query, oldHash).Scan(&login, &family, &tokenExpiresAt, &usedAt, &revokedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("[RotateRefreshToken|exec get token]: %w", ErrRefreshTokenInvalid)
+		}
+		return "", fmt.Errorf("[RotateRefreshToken|exec get token]: %w", err)
+	}
+
+	now := time.Now()
+
+	// отозванный при выходе токен просто недействителен
+	if revokedAt != nil {
+		return "", fmt.Errorf("[RotateRefreshToken|check revoked]: %w", ErrRefreshTokenInvalid)
+	}
+
+	// токен уже был использован - значит, его кто-то украл, отзываем всю цепочку
+	if usedAt != nil {
+		if err := revokeTokenFamily(ctx, tx, family, now); err != nil {
+			return "", fmt.Errorf("[RotateRefreshToken|revoke family]: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return "", fmt.Errorf("[RotateRefreshToken|commit tx]: %w", err)
+		}
+		return "", fmt.Errorf("[RotateRefreshToken|check used]: %w", ErrRefreshTokenReused)
+	}
+
+	if now.After(tokenExpiresAt) {
+		return "", fmt.Errorf("[RotateRefreshToken|check expired]: %w", ErrRefreshTokenExpired)
+	}
+
+	// погасим старый токен и сохраним новый в той же цепочке
+	query = "UPDATE refresh_tokens SET used_at = $1 WHERE token_hash = $2"
+	if _, err := tx.Exec(ctx, query, now, oldHash); err != nil {
+		return "", fmt.Errorf("[RotateRefreshToken|exec use token]: %w", err)
+	}
+
+	query = "INSERT INTO refresh_tokens (login,token_hash,family,expires_at,created_at) VALUES ($1,$2,$3,$4,$5)"
+	if _, err := tx.Exec(ctx, query, login, newHash, family, expiresAt, now); err != nil {
+		return "", fmt.Errorf("[RotateRefreshToken|exec save token]: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("[RotateRefreshToken|commit tx]: %w", err)
+	}
+	return login, nil
+}
+
+func revokeTokenFamily(ctx context.Context, tx pgx.Tx, family string, now time.Time) error {
+	query := "UPDATE refresh_tokens SET revoked_at = $1 WHERE family = $2 AND revoked_at IS NULL"
+	if _, err := tx.Exec(ctx, query, now, family); err != nil {
+		return fmt.Errorf("[revokeTokenFamily|exec revoke]: %w", err)
+	}
+	return nil
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/vk_intern/internal/advertisements"
 	"github.com/vk_intern/internal/users"
 )
 
@@ -62,6 +65,136 @@ func CheckLoginAndPassword(ctx context.Context, user *users.UserRequest) error {
 	return nil
 }
 
+// GetUserRole возвращает роль пользователя
+func GetUserRole(ctx context.Context, login string) (string, error) {
+	var role string
+	query := "SELECT role FROM users WHERE login = $1"
+	if err := Pool.QueryRow(ctx, query, login).Scan(&role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("[GetUserRole|exec get role]: %w", ErrUserLoginWrong)
+		}
+		return "", fmt.Errorf("[GetUserRole|exec get role]: %w", err)
+	}
+	return role, nil
+}
+
+// SetUserRole меняет роль пользователя
+func SetUserRole(ctx context.Context, login, role string) error {
+	query := "UPDATE users SET role = $1 WHERE login = $2"
+	tag, err := Pool.Exec(ctx, query, role, login)
+	if err != nil {
+		return fmt.Errorf("[SetUserRole|exec set role]: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("[SetUserRole|exec set role]: %w", ErrUserLoginWrong)
+	}
+	return nil
+}
+
+// HasRole сообщает, есть ли хотя бы один пользователь с ролью role
+func HasRole(ctx context.Context, role string) (bool, error) {
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM users WHERE role = $1)"
+	if err := Pool.QueryRow(ctx, query, role).Scan(&exists); err != nil {
+		return false, fmt.Errorf("[HasRole|exec has role]: %w", err)
+	}
+	return exists, nil
+}
+
+// GetProfile возвращает профиль пользователя. Телефон остальным пользователям отдается,
+// только если пользователь выбрал связь по телефону, себе - всегда (owner)
+func GetProfile(ctx context.Context, login string, owner bool) (*users.Profile, error) {
+	query := `SELECT u.login,u.display_name,u.avatar_url,u.bio,u.city,u.contact_methods,u.phone,u.created_at,
+				(SELECT COUNT(*) FROM advertisements a WHERE a.login = u.login AND a.status = $2),
+				` + fmt.Sprintf(sellerRatingExpr, "u.login") + `,
+				(SELECT COUNT(*) FROM reviews r WHERE r.seller = u.login)
+			FROM users u
+			WHERE u.login = $1`
+
+	var p users.Profile
+	err := Pool.QueryRow(ctx, query, login, advertisements.StatusActive).Scan(&p.Login, &p.DisplayName, &p.AvatarURL, &p.Bio, &p.City, &p.ContactMethods, &p.Phone, &p.CreatedAt,
+		&p.ActiveAdvertisements, &p.Rating, &p.ReviewsCount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[GetProfile|exec get profile]: %w", ErrUserLoginWrong)
+		}
+		return nil, fmt.Errorf("[GetProfile|exec get profile]: %w", err)
+	}
+
+	if !owner && !users.HasContactMethod(p.ContactMethods, users.ContactPhone) {
+		p.Phone = ""
+	}
+	return &p, nil
+}
+
+// UpdateProfile изменяет переданные поля профиля пользователя и возвращает профиль целиком
+func UpdateProfile(ctx context.Context, login string, upd *users.UpdateProfileRequest) (*users.Profile, error) {
+	tx, err := Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateProfile|begin tx]: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// способы связи проверяются по итоговому профилю, поэтому берем текущие значения
+	var phone string
+	var methods []string
+	query := "SELECT phone,contact_methods FROM users WHERE login = $1 FOR UPDATE"
+	if err := tx.QueryRow(ctx, query, login).Scan(&phone, &methods); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[UpdateProfile|exec get profile]: %w", ErrUserLoginWrong)
+		}
+		return nil, fmt.Errorf("[UpdateProfile|exec get profile]: %w", err)
+	}
+
+	// соберем список изменяемых полей
+	sets := []string{}
+	args := []interface{}{}
+	addSet := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if upd.DisplayName != nil {
+		addSet("display_name", *upd.DisplayName)
+	}
+	if upd.AvatarURL != nil {
+		addSet("avatar_url", *upd.AvatarURL)
+	}
+	if upd.Bio != nil {
+		addSet("bio", *upd.Bio)
+	}
+	if upd.City != nil {
+		addSet("city", *upd.City)
+	}
+	if upd.Phone != nil {
+		phone = *upd.Phone
+		addSet("phone", phone)
+	}
+	if upd.ContactMethods != nil {
+		methods = *upd.ContactMethods
+		addSet("contact_methods", methods)
+	}
+
+	if err := users.ValidateContacts(phone, methods); err != nil {
+		return nil, fmt.Errorf("[UpdateProfile|validate contacts] %w", err)
+	}
+
+	args = append(args, login)
+	query = "UPDATE users SET " + strings.Join(sets, ", ") + fmt.Sprintf(" WHERE login = $%d", len(args))
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("[UpdateProfile|exec update profile]: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[UpdateProfile|commit tx]: %w", err)
+	}
+
+	profile, err := GetProfile(ctx, login, true)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateProfile] %w", err)
+	}
+	return profile, nil
+}
+
 func checkLoginExists(ctx context.Context, login string) (bool, error) {
 	var exists bool
 	query := "SELECT EXISTS(SELECT 1 FROM users WHERE login = $1)"
@@ -0,0 +1,34 @@
+package reviews
+
+import "time"
+
+// Review отзыв покупателя о продавце
+// @Description Модель описывает отзыв покупателя о продавце по объявлению, advertisement_id пустой, если объявление удалено
+type Review struct {
+	ID              int       `json:"id"`
+	AdvertisementID *int      `json:"advertisement_id,omitempty"`
+	Seller          string    `json:"seller"`
+	Author          string    `json:"author"`
+	Rating          int       `json:"rating" example:"5"`
+	Comment         string    `json:"comment,omitempty"`
+	CreatedAt       time.Time `json:"created_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+}
+
+// CreateReviewRequest модель запроса на отзыв
+// @Description Модель описывает оценку продавца от 1 до 5 и необязательный комментарий
+type CreateReviewRequest struct {
+	Rating  int    `json:"rating" validate:"required" example:"5"`
+	Comment string `json:"comment,omitempty" example:"вежливый продавец, все как в описании"`
+}
+
+// ReviewPage страница отзывов о продавце
+// @Description Модель описывает страницу отзывов о продавце, новые идут первыми, вместе со средней оценкой rating
+// @Description и общим количеством отзывов total. rating пустой, пока отзывов нет
+type ReviewPage struct {
+	Items   []*Review `json:"items"`
+	Rating  *float64  `json:"rating,omitempty" example:"4.67"`
+	Total   int       `json:"total"`
+	Page    int       `json:"page"`
+	Limit   int       `json:"limit"`
+	HasNext bool      `json:"has_next"`
+}
@@ -0,0 +1,31 @@
+package reviews
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	minRating     = 1
+	maxRating     = 5
+	maxLenComment = 1000
+)
+
+var (
+	ErrWrongRating = errors.New("rating must be from 1 to 5")
+	ErrLongComment = errors.New("comment is longer than required")
+)
+
+func ValidateReview(review *CreateReviewRequest) error {
+	if review.Rating < minRating || review.Rating > maxRating {
+		return fmt.Errorf("[ValidateReview|rating]: %w", ErrWrongRating)
+	}
+
+	review.Comment = strings.TrimSpace(review.Comment)
+	if utf8.RuneCountInString(review.Comment) > maxLenComment {
+		return fmt.Errorf("[ValidateReview|comment]: %w", ErrLongComment)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/realtime"
+	"github.com/vk_intern/internal/repository"
+)
+
+// хранилище отозванных токенов: источник истины - Postgres, поверх него кеш в памяти.
+// Отзыв кешируется до истечения токена, отсутствие отзыва - на cacheTTL, поэтому отзыв,
+// сделанный на другом экземпляре сервиса, вступает в силу здесь не позже чем через cacheTTL
+var (
+	mu       sync.RWMutex
+	cacheTTL = 10 * time.Second
+	jtis     = map[string]entry{}
+	users    = map[string]userEntry{}
+
+	// открытые подключения пользователей, которым сообщается об отзыве токенов
+	publisher realtime.PubSub
+)
+
+type entry struct {
+	revoked bool
+	until   time.Time // до какого момента запись в кеше актуальна
+}
+
+type userEntry struct {
+	revokedAt *time.Time
+	until     time.Time
+}
+
+// Init задает время жизни непроверенных записей кеша и запускает периодическую
+// очистку истекших записей, очистка останавливается при отмене ctx. Если передан pub,
+// об отзыве токенов сразу сообщается открытым подключениям пользователя
+func Init(ctx context.Context, ttl time.Duration, pub realtime.PubSub) {
+	mu.Lock()
+	cacheTTL = ttl
+	publisher = pub
+	mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cleanup(ctx)
+			}
+		}
+	}()
+}
+
+// RevokeToken отзывает access токен с идентификатором jti до истечения его срока действия exp
+func RevokeToken(ctx context.Context, jti, login string, exp time.Time) error {
+	if err := repository.RevokeJTI(ctx, jti, login, exp); err != nil {
+		return fmt.Errorf("[RevokeToken] %w", err)
+	}
+
+	mu.Lock()
+	jtis[jti] = entry{revoked: true, until: exp}
+	mu.Unlock()
+
+	publishRevoked(ctx, login)
+	return nil
+}
+
+// RevokeAll отзывает все токены пользователя, выпущенные раньше at. Время выпуска токена
+// известно с точностью до секунды, поэтому токен, выданный в ту же секунду, остается действительным
+func RevokeAll(ctx context.Context, login string, at time.Time) error {
+	at = at.Truncate(time.Second)
+	if err := repository.RevokeAllUserTokens(ctx, login, at); err != nil {
+		return fmt.Errorf("[RevokeAll] %w", err)
+	}
+
+	mu.Lock()
+	users[login] = userEntry{revokedAt: &at, until: time.Now().Add(cacheTTL)}
+	mu.Unlock()
+
+	publishRevoked(ctx, login)
+	return nil
+}
+
+// publishRevoked сообщает открытым подключениям пользователя login об отзыве токенов. Отзыв уже
+// сохранен, а подключения еще и периодически перепроверяют токен, поэтому ошибка только логируется
+func publishRevoked(ctx context.Context, login string) {
+	mu.RLock()
+	pub := publisher
+	mu.RUnlock()
+	if pub == nil {
+		return
+	}
+
+	if err := pub.Publish(ctx, login, realtime.Event{Type: realtime.EventRevoked}); err != nil {
+		logger.L.Error("[publishRevoked]:", "login", login, "error", err)
+	}
+}
+
+// IsRevoked проверяет, отозван ли токен jti пользователя login, выпущенный в момент iat
+func IsRevoked(ctx context.Context, jti, login string, iat time.Time) (bool, error) {
+	revoked, err := isJTIRevoked(ctx, jti)
+	if err != nil {
+		return false, fmt.Errorf("[IsRevoked|jti] %w", err)
+	}
+	if revoked {
+		return true, nil
+	}
+
+	revoked, err = isUserTokenRevoked(ctx, login, iat)
+	if err != nil {
+		// токены удаленного пользователя недействительны
+		if errors.Is(err, repository.ErrUserLoginWrong) {
+			return true, nil
+		}
+		return false, fmt.Errorf("[IsRevoked|user] %w", err)
+	}
+	return revoked, nil
+}
+
+func isJTIRevoked(ctx context.Context, jti string) (bool, error) {
+	now := time.Now()
+
+	mu.RLock()
+	e, ok := jtis[jti]
+	mu.RUnlock()
+	if ok && now.Before(e.until) {
+		return e.revoked, nil
+	}
+
+	revoked, err := repository.CheckJTIRevoked(ctx, jti)
+	if err != nil {
+		return false, fmt.Errorf("[isJTIRevoked] %w", err)
+	}
+
+	// отозванный токен остается отозванным, пока он не истечет, поэтому его можно не перепроверять
+	mu.Lock()
+	if !revoked {
+		jtis[jti] = entry{until: now.Add(cacheTTL)}
+	}
+	mu.Unlock()
+	return revoked, nil
+}
+
+func isUserTokenRevoked(ctx context.Context, login string, iat time.Time) (bool, error) {
+	now := time.Now()
+
+	mu.RLock()
+	e, ok := users[login]
+	mu.RUnlock()
+	if ok && now.Before(e.until) {
+		// то же условие, что и в repository.CheckUserTokenRevoked
+		return e.revokedAt != nil && iat.Before(*e.revokedAt), nil
+	}
+
+	revoked, revokedAt, err := repository.CheckUserTokenRevoked(ctx, login, iat)
+	if err != nil {
+		return false, fmt.Errorf("[isUserTokenRevoked] %w", err)
+	}
+
+	mu.Lock()
+	users[login] = userEntry{revokedAt: revokedAt, until: now.Add(cacheTTL)}
+	mu.Unlock()
+	return revoked, nil
+}
+
+// cleanup удаляет истекшие записи из кеша и отозванные токены с истекшим сроком из БД
+func cleanup(ctx context.Context) {
+	now := time.Now()
+
+	mu.Lock()
+	for jti, e := range jtis {
+		if !now.Before(e.until) {
+			delete(jtis, jti)
+		}
+	}
+	for login, e := range users {
+		if !now.Before(e.until) {
+			delete(users, login)
+		}
+	}
+	mu.Unlock()
+
+	if err := repository.DeleteExpiredRevokedTokens(ctx); err != nil {
+		logger.L.Error("[revocation.cleanup]:", "error", err)
+	}
+}
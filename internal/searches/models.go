@@ -0,0 +1,57 @@
+package searches
+
+import "time"
+
+// SavedSearch сохраненный поиск объявлений
+// @Description Модель описывает сохраненный поиск, по которому отбираются новые объявления
+type SavedSearch struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name" example:"iPhone до 50000"`
+	MinPrice  float64   `json:"min_price"`
+	MaxPrice  float64   `json:"max_price" example:"50000"`
+	Query     string    `json:"q,omitempty" example:"iphone"`
+	Category  string    `json:"category,omitempty" example:"phones"`
+	CreatedAt time.Time `json:"created_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+}
+
+// CreateSavedSearchRequest модель запроса на сохранение поиска
+// @Description Модель описывает параметры поиска: диапазон цен, текст и slug категории (вместе с подкатегориями)
+type CreateSavedSearchRequest struct {
+	Name     string   `json:"name" validate:"required" example:"iPhone до 50000"`
+	MinPrice *float64 `json:"min_price,omitempty"`
+	MaxPrice *float64 `json:"max_price,omitempty" example:"50000"`
+	Query    string   `json:"q,omitempty" example:"iphone"`
+	Category string   `json:"category,omitempty" example:"phones"`
+}
+
+// MatchedAdvertisement объявление, подошедшее под сохраненный поиск
+// @Description Модель описывает новое объявление, которое подошло под один из сохраненных поисков пользователя
+type MatchedAdvertisement struct {
+	SavedSearchID   int       `json:"saved_search_id"`
+	SavedSearchName string    `json:"saved_search_name" example:"iPhone до 50000"`
+	AdvertisementID int       `json:"advertisement_id"`
+	Title           string    `json:"title"`
+	Price           float64   `json:"price"`
+	ImageURL        string    `json:"image_url"`
+	MatchedAt       time.Time `json:"matched_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+}
+
+// MatchedAdvertisementPage страница объявлений, подошедших под сохраненные поиски
+// @Description Модель описывает страницу совпадений по сохраненным поискам, новые совпадения идут первыми
+type MatchedAdvertisementPage struct {
+	Items   []*MatchedAdvertisement `json:"items"`
+	Total   int                     `json:"total"`
+	Page    int                     `json:"page"`
+	Limit   int                     `json:"limit"`
+	HasNext bool                    `json:"has_next"`
+}
+
+// SavedSearchMatch сохраненный поиск, под который подошло новое объявление
+type SavedSearchMatch struct {
+	SavedSearchID   int
+	SavedSearchName string
+	Login           string
+	AdvertisementID int
+	Title           string
+	Price           float64
+}
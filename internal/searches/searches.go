@@ -0,0 +1,59 @@
+package searches
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vk_intern/internal/advertisements"
+)
+
+const (
+	maxLenName = 100
+
+	// MaxSavedSearches сколько поисков может сохранить один пользователь
+	MaxSavedSearches = 20
+)
+
+var (
+	ErrEmptyName            = errors.New("saved search name is required")
+	ErrLongName             = errors.New("saved search name is longer than required")
+	ErrEmptySearch          = errors.New("saved search has no conditions")
+	ErrTooManySavedSearches = errors.New("too many saved searches")
+)
+
+// ValidateSavedSearch проверяет параметры поиска по тем же правилам, что и фильтр ленты объявлений,
+// и возвращает их в виде фильтра. Существование категории проверяется отдельно
+func ValidateSavedSearch(req *CreateSavedSearchRequest) (*advertisements.AdvertisementFilter, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("[ValidateSavedSearch|name]: %w", ErrEmptyName)
+	}
+	if utf8.RuneCountInString(req.Name) > maxLenName {
+		return nil, fmt.Errorf("[ValidateSavedSearch|name]: %w", ErrLongName)
+	}
+
+	// поиск без условий подходил бы под каждое новое объявление
+	if req.MinPrice == nil && req.MaxPrice == nil && strings.TrimSpace(req.Query) == "" && req.Category == "" {
+		return nil, fmt.Errorf("[ValidateSavedSearch]: %w", ErrEmptySearch)
+	}
+
+	filter := advertisements.NewDefaultFilter()
+	if req.MinPrice != nil {
+		filter.MinPrice = *req.MinPrice
+	}
+	if req.MaxPrice != nil {
+		filter.MaxPrice = *req.MaxPrice
+	}
+	filter.Query = req.Query
+	filter.Category = req.Category
+
+	if err := advertisements.ValidatePricesInAdverisementFilter(&filter); err != nil {
+		return nil, fmt.Errorf("[ValidateSavedSearch] %w", err)
+	}
+	if err := advertisements.ValidateSearchInAdvertisementFilter(&filter); err != nil {
+		return nil, fmt.Errorf("[ValidateSavedSearch] %w", err)
+	}
+	return &filter, nil
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage хранит файлы в одной директории локальной файловой системы
+type LocalStorage struct {
+	dir string
+}
+
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("[NewLocalStorage|create dir] %w", err)
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+func (s *LocalStorage) Save(ctx context.Context, name string, r io.Reader) error {
+	path, err := s.path(name)
+	if err != nil {
+		return fmt.Errorf("[LocalStorage.Save] %w", err)
+	}
+
+	// пишем во временный файл и переименовываем, чтобы не отдавать недописанный файл
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("[LocalStorage.Save|create temp] %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("[LocalStorage.Save|write] %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("[LocalStorage.Save|close] %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("[LocalStorage.Save|rename] %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, fmt.Errorf("[LocalStorage.Open] %w", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("[LocalStorage.Open]: %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("[LocalStorage.Open] %w", err)
+	}
+	return f, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return fmt.Errorf("[LocalStorage.Delete] %w", err)
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("[LocalStorage.Delete] %w", err)
+	}
+	return nil
+}
+
+// path возвращает путь к файлу, не позволяя выйти за пределы директории хранилища
+func (s *LocalStorage) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name[0] == '.' {
+		return "", fmt.Errorf("[LocalStorage.path]: %w", ErrInvalidName)
+	}
+	return filepath.Join(s.dir, name), nil
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrNotFound    = errors.New("file not found in storage")
+	ErrInvalidName = errors.New("invalid file name")
+)
+
+// Storage хранилище загруженных файлов. Реализация на локальной файловой системе - LocalStorage,
+// для S3-совместимых хранилищ достаточно реализовать этот же интерфейс
+type Storage interface {
+	// Save сохраняет содержимое r под именем name
+	Save(ctx context.Context, name string, r io.Reader) error
+	// Open открывает файл name на чтение, если файла нет - возвращает ErrNotFound
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// Delete удаляет файл name, отсутствие файла ошибкой не считается
+	Delete(ctx context.Context, name string) error
+}
+
+// RandomName генерирует случайное имя файла с расширением ext
+func RandomName(ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("[RandomName|read rand] %w", err)
+	}
+	return hex.EncodeToString(b) + ext, nil
+}
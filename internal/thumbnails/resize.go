@@ -0,0 +1,72 @@
+package thumbnails
+
+import (
+	"image"
+	"image/draw"
+)
+
+// fitSize возвращает размеры изображения w x h, вписанного в квадрат max x max
+// с сохранением пропорций. Изображения меньше квадрата не увеличиваются
+func fitSize(w, h, max int) (int, int) {
+	if w <= max && h <= max {
+		return w, h
+	}
+	if w >= h {
+		return max, maxInt(1, h*max/w)
+	}
+	return maxInt(1, w*max/h), max
+}
+
+// resize уменьшает изображение усреднением по областям исходных пикселей
+func resize(src image.Image, w, h int) *image.RGBA {
+	// приведем исходное изображение к RGBA, чтобы читать пиксели напрямую
+	b := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok || b.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	}
+
+	sw, sh := rgba.Bounds().Dx(), rgba.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if sw == w && sh == h {
+		draw.Draw(dst, dst.Bounds(), rgba, image.Point{}, draw.Src)
+		return dst
+	}
+
+	for y := 0; y < h; y++ {
+		y0 := y * sh / h
+		y1 := maxInt(y0+1, (y+1)*sh/h)
+		for x := 0; x < w; x++ {
+			x0 := x * sw / w
+			x1 := maxInt(x0+1, (x+1)*sw/w)
+
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				off := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(rgba.Pix[off])
+					g += uint32(rgba.Pix[off+1])
+					bl += uint32(rgba.Pix[off+2])
+					a += uint32(rgba.Pix[off+3])
+					off += 4
+					n++
+				}
+			}
+
+			d := dst.PixOffset(x, y)
+			dst.Pix[d] = uint8(r / n)
+			dst.Pix[d+1] = uint8(g / n)
+			dst.Pix[d+2] = uint8(bl / n)
+			dst.Pix[d+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,122 @@
+package thumbnails
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/vk_intern/internal/advertisements"
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/storage"
+)
+
+const jpegQuality = 80
+
+var (
+	ErrUnsupportedFormat = errors.New("thumbnails are not supported for this image format")
+)
+
+// Generator в фоне создает миниатюры для загруженных фотографий
+type Generator struct {
+	store     storage.Storage
+	queue     chan string
+	maxPixels int
+}
+
+func NewGenerator(store storage.Storage, queueSize, maxPixels int) *Generator {
+	return &Generator{
+		store:     store,
+		queue:     make(chan string, queueSize),
+		maxPixels: maxPixels,
+	}
+}
+
+// Run запускает workers обработчиков очереди, обработчики завершаются при отмене ctx
+func (g *Generator) Run(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case name := <-g.queue:
+					if err := g.Generate(ctx, name); err != nil {
+						logger.L.Error("[Generator.Run | generate]:", "name", name, "error", err)
+					}
+				}
+			}
+		}()
+	}
+}
+
+// Enqueue ставит фотографию в очередь на создание миниатюр. Если очередь заполнена,
+// фотография пропускается: вместо миниатюр будет отдаваться исходный файл
+func (g *Generator) Enqueue(name string) {
+	select {
+	case g.queue <- name:
+	default:
+		logger.L.Error("[Generator.Enqueue]: queue is full, skip thumbnails", "name", name)
+	}
+}
+
+// Generate создает и сохраняет миниатюры всех размеров для фотографии name
+func (g *Generator) Generate(ctx context.Context, name string) error {
+	ext := strings.ToLower(path.Ext(name))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		// webp декодируется для проверки размеров, но кодировщика webp нет ни в стандартной библиотеке,
+		// ни в golang.org/x/image, поэтому миниатюры в формате оригинала для него не создать
+		logger.L.Info("[Generator.Generate]: skip thumbnails", "name", name, "reason", ErrUnsupportedFormat)
+		return nil
+	}
+
+	file, err := g.store.Open(ctx, name)
+	if err != nil {
+		return fmt.Errorf("[Generator.Generate|open] %w", err)
+	}
+	defer file.Close()
+
+	// перед декодированием проверим разрешение по заголовку, прочитанный заголовок
+	// сохраняется в header и затем передается декодеру вместе с остатком файла
+	var header bytes.Buffer
+	if err := advertisements.CheckImageResolution(io.TeeReader(file, &header), g.maxPixels); err != nil {
+		return fmt.Errorf("[Generator.Generate|check resolution] %w", err)
+	}
+
+	src, _, err := image.Decode(io.MultiReader(&header, file))
+	if err != nil {
+		return fmt.Errorf("[Generator.Generate|decode] %w", err)
+	}
+
+	b := src.Bounds()
+	for _, size := range advertisements.ThumbnailSizes {
+		w, h := fitSize(b.Dx(), b.Dy(), size.Max)
+		thumb := resize(src, w, h)
+
+		var buf bytes.Buffer
+		if err := encode(&buf, thumb, ext); err != nil {
+			return fmt.Errorf("[Generator.Generate|encode %s] %w", size.Name, err)
+		}
+
+		if err := g.store.Save(ctx, advertisements.ThumbnailName(name, size.Name), &buf); err != nil {
+			return fmt.Errorf("[Generator.Generate|save %s] %w", size.Name, err)
+		}
+	}
+
+	logger.L.Info("[Generator.Generate]: thumbnails created", "name", name)
+	return nil
+}
+
+// encode сохраняет миниатюру в том же формате, что и исходная фотография
+func encode(w io.Writer, img image.Image, ext string) error {
+	if ext == ".png" {
+		return png.Encode(w, img)
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+}
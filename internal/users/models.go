@@ -15,3 +15,66 @@ type UserRegisterResponse struct {
 	Login      string    `json:"login"`
 	Created_at time.Time `json:"created_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
 }
+
+// TokenResponse модель ответа с парой токенов
+// @Description Модель описывает access токен для заголовка Authorization и refresh токен для его обновления
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in" example:"900"` // время действия access токена в секундах
+}
+
+// RefreshRequest модель запроса на обновление токенов
+// @Description Модель описывает запрос на получение новой пары токенов по refresh токену
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// LogoutRequest модель запроса на выход
+// @Description Модель описывает необязательный refresh токен, который нужно отозвать вместе с текущим access токеном
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// SetRoleRequest модель запроса на смену роли пользователя
+// @Description Модель описывает новую роль пользователя: user, moderator или admin
+type SetRoleRequest struct {
+	Role string `json:"role" validate:"required" example:"moderator"`
+}
+
+// UserRoleResponse модель ответа на смену роли
+// @Description Модель описывает логин пользователя и его текущую роль
+type UserRoleResponse struct {
+	Login string `json:"login"`
+	Role  string `json:"role"`
+}
+
+// Profile профиль пользователя
+// @Description Модель описывает публичный профиль пользователя со счетчиком опубликованных объявлений и оценкой как продавца.
+// @Description Телефон виден остальным, только если среди способов связи есть phone
+type Profile struct {
+	Login                string    `json:"login"`
+	DisplayName          string    `json:"display_name,omitempty"`
+	AvatarURL            string    `json:"avatar_url,omitempty"`
+	Bio                  string    `json:"bio,omitempty"`
+	City                 string    `json:"city,omitempty"`
+	ContactMethods       []string  `json:"contact_methods" example:"messages,phone"`
+	Phone                string    `json:"phone,omitempty" example:"+79991234567"`
+	ActiveAdvertisements int       `json:"active_advertisements"`
+	Rating               *float64  `json:"rating,omitempty" example:"4.67"`
+	ReviewsCount         int       `json:"reviews_count"`
+	CreatedAt            time.Time `json:"created_at" example:"2023-05-15T10:00:00Z" format:"date-time"`
+}
+
+// UpdateProfileRequest модель запроса на изменение профиля
+// @Description Модель описывает запрос на изменение профиля, передаются только изменяемые поля, пустая строка очищает поле.
+// @Description Способы связи: messages (сообщения по объявлениям), phone (телефон в профиле).
+// @Description Аватар - адрес фотографии, загруженной через POST /images
+type UpdateProfileRequest struct {
+	DisplayName    *string   `json:"display_name,omitempty"`
+	AvatarURL      *string   `json:"avatar_url,omitempty" example:"/images/3f2a9c1e.jpg"`
+	Bio            *string   `json:"bio,omitempty"`
+	City           *string   `json:"city,omitempty"`
+	Phone          *string   `json:"phone,omitempty" example:"+79991234567"`
+	ContactMethods *[]string `json:"contact_methods,omitempty"`
+}
@@ -0,0 +1,27 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+)
+
+// роли пользователей
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Roles все роли, модератор проверяет объявления, администратор дополнительно управляет ролями
+var Roles = []string{RoleUser, RoleModerator, RoleAdmin}
+
+var ErrUnknownRole = errors.New("unknown user role")
+
+func ValidateRole(role string) error {
+	for _, r := range Roles {
+		if r == role {
+			return nil
+		}
+	}
+	return fmt.Errorf("[ValidateRole]: %w", ErrUnknownRole)
+}
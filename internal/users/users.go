@@ -3,9 +3,13 @@ package users
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"path"
 	"regexp"
+	"strings"
 	"unicode/utf8"
 
+	"github.com/vk_intern/internal/advertisements"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -14,8 +18,21 @@ const (
 	maxLenLogin    = 25
 	minLenPassword = 8
 	maxLenPassword = 25
+
+	maxLenDisplayName = 50
+	maxLenAvatarURL   = 500
+	maxLenBio         = 1000
+	maxLenCity        = 100
+)
+
+// способы связи с пользователем
+const (
+	ContactMessages = "messages"
+	ContactPhone    = "phone"
 )
 
+var ContactMethods = []string{ContactMessages, ContactPhone}
+
 var (
 	ErrShortLogin           = errors.New("login is shorter than required")
 	ErrLongLogin            = errors.New("login is longer than required")
@@ -23,8 +40,20 @@ var (
 	ErrShortPassword        = errors.New("password is shorter than required")
 	ErrLongPassword         = errors.New("password is longer than required")
 	ErrWrongPasswordSymbols = errors.New("wrong symbols in password")
+
+	ErrEmptyProfileUpdate   = errors.New("no profile fields to update")
+	ErrLongDisplayName      = errors.New("display name is longer than required")
+	ErrWrongAvatarURL       = errors.New("wrong avatar url")
+	ErrLongBio              = errors.New("bio is longer than required")
+	ErrLongCity             = errors.New("city is longer than required")
+	ErrWrongPhone           = errors.New("wrong phone number")
+	ErrUnknownContactMethod = errors.New("unknown contact method")
+	ErrEmptyContactMethods  = errors.New("at least one contact method is required")
+	ErrPhoneRequired        = errors.New("phone is required for contact method phone")
 )
 
+var phoneRegexp = regexp.MustCompile(`^\+?[0-9]{10,15}$`)
+
 func ValidateUserLoginPassword(user *UserRequest) error {
 	if err := vaildateLogin(user.Login); err != nil {
 		return fmt.Errorf("[ValidateUserLoginPassword] %w", err)
@@ -71,6 +100,128 @@ func validatePassword(pass string) error {
 	return nil
 }
 
+// ValidateUpdateProfile проверяет переданные поля профиля и приводит их к виду для хранения.
+// Наличие телефона для способа связи phone проверяет ValidateContacts по итоговому профилю
+func ValidateUpdateProfile(upd *UpdateProfileRequest) error {
+	if upd.DisplayName == nil && upd.AvatarURL == nil && upd.Bio == nil && upd.City == nil &&
+		upd.Phone == nil && upd.ContactMethods == nil {
+		return fmt.Errorf("[ValidateUpdateProfile]: %w", ErrEmptyProfileUpdate)
+	}
+
+	if upd.DisplayName != nil {
+		*upd.DisplayName = strings.TrimSpace(*upd.DisplayName)
+		if utf8.RuneCountInString(*upd.DisplayName) > maxLenDisplayName {
+			return fmt.Errorf("[ValidateUpdateProfile|display name]: %w", ErrLongDisplayName)
+		}
+	}
+
+	if upd.AvatarURL != nil && *upd.AvatarURL != "" {
+		if err := validateAvatarURL(*upd.AvatarURL); err != nil {
+			return fmt.Errorf("[ValidateUpdateProfile|avatar] %w", err)
+		}
+	}
+
+	if upd.Bio != nil {
+		*upd.Bio = strings.TrimSpace(*upd.Bio)
+		if utf8.RuneCountInString(*upd.Bio) > maxLenBio {
+			return fmt.Errorf("[ValidateUpdateProfile|bio]: %w", ErrLongBio)
+		}
+	}
+
+	if upd.City != nil {
+		*upd.City = strings.TrimSpace(*upd.City)
+		if utf8.RuneCountInString(*upd.City) > maxLenCity {
+			return fmt.Errorf("[ValidateUpdateProfile|city]: %w", ErrLongCity)
+		}
+	}
+
+	if upd.Phone != nil {
+		// пробелы, скобки и дефисы в номере не храним
+		*upd.Phone = strings.NewReplacer(" ", "", "(", "", ")", "", "-", "").Replace(*upd.Phone)
+		if *upd.Phone != "" && !phoneRegexp.MatchString(*upd.Phone) {
+			return fmt.Errorf("[ValidateUpdateProfile|phone]: %w", ErrWrongPhone)
+		}
+	}
+
+	if upd.ContactMethods != nil {
+		methods, err := normalizeContactMethods(*upd.ContactMethods)
+		if err != nil {
+			return fmt.Errorf("[ValidateUpdateProfile|contact methods] %w", err)
+		}
+		*upd.ContactMethods = methods
+	}
+	return nil
+}
+
+// ValidateContacts проверяет, что для выбранных способов связи хватает данных профиля
+func ValidateContacts(phone string, methods []string) error {
+	for _, m := range methods {
+		if m == ContactPhone && phone == "" {
+			return fmt.Errorf("[ValidateContacts]: %w", ErrPhoneRequired)
+		}
+	}
+	return nil
+}
+
+// HasContactMethod сообщает, выбран ли способ связи method
+func HasContactMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func validateAvatarURL(avatarURL string) error {
+	if utf8.RuneCountInString(avatarURL) > maxLenAvatarURL {
+		return fmt.Errorf("[validateAvatarURL]: %w", ErrWrongAvatarURL)
+	}
+
+	u, err := url.Parse(avatarURL)
+	if err != nil {
+		return fmt.Errorf("[validateAvatarURL]: %w", ErrWrongAvatarURL)
+	}
+
+	// аватар - это фотография, загруженная в сервис через POST /images, поэтому сторонние адреса
+	// и другие схемы (javascript:, data:) в профиль не попадут
+	if u.Scheme != "" || u.Host != "" || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("[validateAvatarURL]: %w", ErrWrongAvatarURL)
+	}
+	name, ok := strings.CutPrefix(u.Path, advertisements.ImagesURLPrefix)
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("[validateAvatarURL]: %w", ErrWrongAvatarURL)
+	}
+
+	// в одном из форматов фотографий объявлений
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range advertisements.ImageFormats {
+		if e == ext {
+			return nil
+		}
+	}
+	return fmt.Errorf("[validateAvatarURL]: %w", ErrWrongAvatarURL)
+}
+
+// normalizeContactMethods проверяет способы связи и убирает повторы
+func normalizeContactMethods(methods []string) ([]string, error) {
+	normalized := []string{}
+	for _, m := range methods {
+		m = strings.TrimSpace(m)
+		if !HasContactMethod(ContactMethods, m) {
+			return nil, fmt.Errorf("[normalizeContactMethods]: %w", ErrUnknownContactMethod)
+		}
+		if !HasContactMethod(normalized, m) {
+			normalized = append(normalized, m)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("[normalizeContactMethods]: %w", ErrEmptyContactMethods)
+	}
+	return normalized, nil
+}
+
 func HashPassword(password string) (string, error) {
 	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
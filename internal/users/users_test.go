@@ -0,0 +1,161 @@
+package users
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestValidateUpdateProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		upd     UpdateProfileRequest
+		want    UpdateProfileRequest
+		wantErr error
+	}{
+		{name: "empty update", upd: UpdateProfileRequest{}, wantErr: ErrEmptyProfileUpdate},
+		{
+			name: "trims text fields",
+			upd:  UpdateProfileRequest{DisplayName: ptr("  Иван  "), Bio: ptr(" продаю технику "), City: ptr(" Москва ")},
+			want: UpdateProfileRequest{DisplayName: ptr("Иван"), Bio: ptr("продаю технику"), City: ptr("Москва")},
+		},
+		{name: "long display name", upd: UpdateProfileRequest{DisplayName: ptr(strings.Repeat("я", maxLenDisplayName+1))}, wantErr: ErrLongDisplayName},
+		{name: "long bio", upd: UpdateProfileRequest{Bio: ptr(strings.Repeat("я", maxLenBio+1))}, wantErr: ErrLongBio},
+		{name: "long city", upd: UpdateProfileRequest{City: ptr(strings.Repeat("я", maxLenCity+1))}, wantErr: ErrLongCity},
+		{
+			name: "clear avatar",
+			upd:  UpdateProfileRequest{AvatarURL: ptr("")},
+			want: UpdateProfileRequest{AvatarURL: ptr("")},
+		},
+		{name: "foreign avatar", upd: UpdateProfileRequest{AvatarURL: ptr("https://example.com/a.jpg")}, wantErr: ErrWrongAvatarURL},
+		{
+			name: "normalizes phone",
+			upd:  UpdateProfileRequest{Phone: ptr("+7 (999) 123-45-67")},
+			want: UpdateProfileRequest{Phone: ptr("+79991234567")},
+		},
+		{
+			name: "clear phone",
+			upd:  UpdateProfileRequest{Phone: ptr("")},
+			want: UpdateProfileRequest{Phone: ptr("")},
+		},
+		{name: "wrong phone", upd: UpdateProfileRequest{Phone: ptr("12-34")}, wantErr: ErrWrongPhone},
+		{
+			name: "dedups contact methods",
+			upd:  UpdateProfileRequest{ContactMethods: ptr([]string{ContactPhone, " phone ", ContactMessages})},
+			want: UpdateProfileRequest{ContactMethods: ptr([]string{ContactPhone, ContactMessages})},
+		},
+		{name: "unknown contact method", upd: UpdateProfileRequest{ContactMethods: ptr([]string{"email"})}, wantErr: ErrUnknownContactMethod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdateProfile(&tt.upd)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ValidateUpdateProfile() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateUpdateProfile() unexpected error: %v", err)
+			}
+
+			for _, f := range []struct {
+				field     string
+				got, want *string
+			}{
+				{"display_name", tt.upd.DisplayName, tt.want.DisplayName},
+				{"avatar_url", tt.upd.AvatarURL, tt.want.AvatarURL},
+				{"bio", tt.upd.Bio, tt.want.Bio},
+				{"city", tt.upd.City, tt.want.City},
+				{"phone", tt.upd.Phone, tt.want.Phone},
+			} {
+				if (f.got == nil) != (f.want == nil) || (f.got != nil && *f.got != *f.want) {
+					t.Fatalf("%s = %v, want %v", f.field, f.got, f.want)
+				}
+			}
+			if (tt.upd.ContactMethods == nil) != (tt.want.ContactMethods == nil) ||
+				(tt.upd.ContactMethods != nil && !slices.Equal(*tt.upd.ContactMethods, *tt.want.ContactMethods)) {
+				t.Fatalf("contact_methods = %v, want %v", tt.upd.ContactMethods, tt.want.ContactMethods)
+			}
+		})
+	}
+}
+
+func TestNormalizeContactMethods(t *testing.T) {
+	tests := []struct {
+		name    string
+		methods []string
+		want    []string
+		wantErr error
+	}{
+		{name: "single", methods: []string{ContactMessages}, want: []string{ContactMessages}},
+		{name: "keeps order", methods: []string{ContactPhone, ContactMessages}, want: []string{ContactPhone, ContactMessages}},
+		{name: "trims and dedups", methods: []string{" messages", ContactMessages, "phone "}, want: []string{ContactMessages, ContactPhone}},
+		{name: "empty", methods: []string{}, wantErr: ErrEmptyContactMethods},
+		{name: "unknown", methods: []string{ContactMessages, "telegram"}, wantErr: ErrUnknownContactMethod},
+		{name: "blank", methods: []string{" "}, wantErr: ErrUnknownContactMethod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeContactMethods(tt.methods)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("normalizeContactMethods(%q) error = %v, want %v", tt.methods, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeContactMethods(%q) unexpected error: %v", tt.methods, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("normalizeContactMethods(%q) = %q, want %q", tt.methods, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAvatarURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "/images/3f2a9c1e.jpg"},
+		{url: "/images/3f2a9c1e.JPEG"},
+		{url: "/images/3f2a9c1e.png"},
+		{url: "/images/3f2a9c1e.webp"},
+		{url: "/images/3f2a9c1e.gif", wantErr: true},
+		{url: "/images/3f2a9c1e", wantErr: true},
+		{url: "/images/", wantErr: true},
+		{url: "/images/thumbs/3f2a9c1e.jpg", wantErr: true},
+		{url: "/uploads/3f2a9c1e.jpg", wantErr: true},
+		{url: "/images/3f2a9c1e.jpg?size=small", wantErr: true},
+		{url: "/images/3f2a9c1e.jpg#top", wantErr: true},
+		{url: "https://example.com/images/3f2a9c1e.jpg", wantErr: true},
+		{url: "//example.com/images/3f2a9c1e.jpg", wantErr: true},
+		{url: "javascript:alert(1)", wantErr: true},
+		{url: "data:image/png;base64,AAAA", wantErr: true},
+		{url: "/images/" + strings.Repeat("a", maxLenAvatarURL) + ".jpg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validateAvatarURL(tt.url)
+			if tt.wantErr {
+				if !errors.Is(err, ErrWrongAvatarURL) {
+					t.Fatalf("validateAvatarURL(%q) error = %v, want %v", tt.url, err, ErrWrongAvatarURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateAvatarURL(%q) unexpected error: %v", tt.url, err)
+			}
+		})
+	}
+}
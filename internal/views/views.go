@@ -0,0 +1,91 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vk_intern/internal/logger"
+	"github.com/vk_intern/internal/repository"
+)
+
+// как часто старые просмотры сворачиваются в счетчики объявлений
+const aggregatePeriod = time.Hour
+
+// view просмотр карточки объявления id зрителем viewer
+type view struct {
+	id     int
+	viewer string
+}
+
+// Recorder в фоне записывает просмотры карточек объявлений, чтобы показ карточки не ждал записи в БД,
+// и сворачивает просмотры старше retentionDays дней в счетчики объявлений
+type Recorder struct {
+	queue         chan view
+	retentionDays int
+}
+
+func NewRecorder(queueSize, retentionDays int) *Recorder {
+	return &Recorder{
+		queue:         make(chan view, queueSize),
+		retentionDays: retentionDays,
+	}
+}
+
+// Run запускает workers обработчиков очереди и периодическое сворачивание старых просмотров,
+// все они завершаются при отмене ctx
+func (r *Recorder) Run(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case v := <-r.queue:
+					if err := repository.RecordAdvertisementView(ctx, v.id, v.viewer); err != nil {
+						logger.L.Error("[Recorder.Run | record view]:", "id", v.id, "error", err)
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(aggregatePeriod)
+		defer ticker.Stop()
+
+		for {
+			if err := r.Aggregate(ctx); err != nil {
+				logger.L.Error("[Recorder.Run | aggregate]:", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Enqueue ставит просмотр объявления id в очередь на запись. Если очередь заполнена,
+// просмотр пропускается и в статистику не попадет
+func (r *Recorder) Enqueue(id int, viewer string) {
+	select {
+	case r.queue <- view{id: id, viewer: viewer}:
+	default:
+		logger.L.Warn("[Recorder.Enqueue]: queue is full, skip view", "id", id)
+	}
+}
+
+// Aggregate переносит просмотры старше retentionDays дней в счетчики объявлений
+func (r *Recorder) Aggregate(ctx context.Context) error {
+	count, err := repository.AggregateAdvertisementViews(ctx, r.retentionDays)
+	if err != nil {
+		return fmt.Errorf("[Recorder.Aggregate] %w", err)
+	}
+	if count > 0 {
+		logger.L.Info("[Recorder.Aggregate]: views aggregated", "advertisements", count)
+	}
+	return nil
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_advertisements_status;
+ALTER TABLE advertisements DROP COLUMN IF EXISTS status;
\ No newline at end of file
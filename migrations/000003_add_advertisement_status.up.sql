@@ -0,0 +1,5 @@
+ALTER TABLE advertisements
+	ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+	CHECK (status IN ('draft', 'active', 'reserved', 'sold', 'archived'));
+
+CREATE INDEX IF NOT EXISTS idx_advertisements_status ON advertisements (status);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE advertisements DROP COLUMN IF EXISTS category_id;
+DROP TABLE categories
\ No newline at end of file
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS categories (
+			id SERIAL PRIMARY KEY,
+			parent_id INT REFERENCES categories(id) ON DELETE RESTRICT,
+			slug VARCHAR(100) UNIQUE NOT NULL,
+			name VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+INSERT INTO categories (slug, name) VALUES
+	('electronics', 'Электроника'),
+	('transport', 'Транспорт'),
+	('realty', 'Недвижимость'),
+	('clothes', 'Одежда и обувь'),
+	('home', 'Дом и сад'),
+	('other', 'Другое');
+
+INSERT INTO categories (parent_id, slug, name) VALUES
+	((SELECT id FROM categories WHERE slug = 'electronics'), 'phones', 'Телефоны'),
+	((SELECT id FROM categories WHERE slug = 'electronics'), 'computers', 'Компьютеры и ноутбуки'),
+	((SELECT id FROM categories WHERE slug = 'electronics'), 'audio-video', 'Аудио и видео'),
+	((SELECT id FROM categories WHERE slug = 'transport'), 'cars', 'Автомобили'),
+	((SELECT id FROM categories WHERE slug = 'transport'), 'motorcycles', 'Мотоциклы'),
+	((SELECT id FROM categories WHERE slug = 'transport'), 'spare-parts', 'Запчасти'),
+	((SELECT id FROM categories WHERE slug = 'realty'), 'apartments', 'Квартиры'),
+	((SELECT id FROM categories WHERE slug = 'realty'), 'houses', 'Дома'),
+	((SELECT id FROM categories WHERE slug = 'clothes'), 'womens-clothes', 'Женская одежда'),
+	((SELECT id FROM categories WHERE slug = 'clothes'), 'mens-clothes', 'Мужская одежда'),
+	((SELECT id FROM categories WHERE slug = 'home'), 'furniture', 'Мебель'),
+	((SELECT id FROM categories WHERE slug = 'home'), 'appliances', 'Бытовая техника');
+
+ALTER TABLE advertisements
+	ADD COLUMN IF NOT EXISTS category_id INT REFERENCES categories(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_advertisements_category_id ON advertisements (category_id);
\ No newline at end of file
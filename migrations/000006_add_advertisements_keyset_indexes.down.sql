@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_advertisements_price_id;
+DROP INDEX IF EXISTS idx_advertisements_created_at_id;
\ No newline at end of file
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_advertisements_created_at_id ON advertisements (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_advertisements_price_id ON advertisements (price, id);
\ No newline at end of file
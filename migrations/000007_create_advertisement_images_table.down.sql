@@ -0,0 +1 @@
+DROP TABLE advertisement_images
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS advertisement_images (
+			id SERIAL PRIMARY KEY,
+			advertisement_id INT NOT NULL REFERENCES advertisements(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			position INT NOT NULL,
+			is_cover BOOLEAN NOT NULL DEFAULT FALSE,
+			UNIQUE (advertisement_id, position)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_advertisement_images_cover ON advertisement_images (advertisement_id) WHERE is_cover;
+
+INSERT INTO advertisement_images (advertisement_id, url, position, is_cover)
+	SELECT id, image_url, 0, TRUE FROM advertisements;
\ No newline at end of file
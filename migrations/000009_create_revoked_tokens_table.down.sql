@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS tokens_revoked_at;
+DROP TABLE revoked_tokens
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+			jti VARCHAR(32) PRIMARY KEY,
+			login VARCHAR(100) NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+			expires_at TIMESTAMP NOT NULL,
+			revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tokens_revoked_at TIMESTAMP;
\ No newline at end of file
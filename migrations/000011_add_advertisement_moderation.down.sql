@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_advertisements_pending;
+ALTER TABLE advertisements
+	DROP COLUMN IF EXISTS moderation_reason,
+	DROP COLUMN IF EXISTS moderated_by,
+	DROP COLUMN IF EXISTS moderated_at;
+
+UPDATE advertisements SET status = 'draft' WHERE status IN ('pending', 'rejected');
+ALTER TABLE advertisements DROP CONSTRAINT IF EXISTS advertisements_status_check;
+ALTER TABLE advertisements ADD CONSTRAINT advertisements_status_check
+	CHECK (status IN ('draft', 'active', 'reserved', 'sold', 'archived'));
\ No newline at end of file
@@ -0,0 +1,10 @@
+ALTER TABLE advertisements DROP CONSTRAINT IF EXISTS advertisements_status_check;
+ALTER TABLE advertisements ADD CONSTRAINT advertisements_status_check
+	CHECK (status IN ('draft', 'pending', 'active', 'rejected', 'reserved', 'sold', 'archived'));
+
+ALTER TABLE advertisements
+	ADD COLUMN IF NOT EXISTS moderation_reason TEXT,
+	ADD COLUMN IF NOT EXISTS moderated_by VARCHAR(100),
+	ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_advertisements_pending ON advertisements (created_at, id) WHERE status = 'pending';
\ No newline at end of file
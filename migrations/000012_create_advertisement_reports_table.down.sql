@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS advertisement_reports;
+
+UPDATE advertisements SET status = 'draft' WHERE status = 'hidden';
+ALTER TABLE advertisements DROP CONSTRAINT IF EXISTS advertisements_status_check;
+ALTER TABLE advertisements ADD CONSTRAINT advertisements_status_check
+	CHECK (status IN ('draft', 'pending', 'active', 'rejected', 'reserved', 'sold', 'archived'));
\ No newline at end of file
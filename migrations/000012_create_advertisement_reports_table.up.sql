@@ -0,0 +1,15 @@
+ALTER TABLE advertisements DROP CONSTRAINT IF EXISTS advertisements_status_check;
+ALTER TABLE advertisements ADD CONSTRAINT advertisements_status_check
+	CHECK (status IN ('draft', 'pending', 'active', 'rejected', 'hidden', 'reserved', 'sold', 'archived'));
+
+CREATE TABLE IF NOT EXISTS advertisement_reports (
+			id SERIAL PRIMARY KEY,
+			advertisement_id INT NOT NULL REFERENCES advertisements(id) ON DELETE CASCADE,
+			login VARCHAR(100) NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+			reason VARCHAR(20) NOT NULL CHECK (reason IN ('fraud', 'prohibited', 'wrong_category', 'duplicate', 'offensive', 'other')),
+			comment TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			resolved_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_advertisement_reports_open ON advertisement_reports (advertisement_id, login) WHERE resolved_at IS NULL;
\ No newline at end of file
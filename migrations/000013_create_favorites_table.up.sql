@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS favorites (
+			login VARCHAR(100) NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+			advertisement_id INT NOT NULL REFERENCES advertisements(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (login, advertisement_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorites_login_created_at ON favorites (login, created_at DESC);
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS saved_searches (
+			id SERIAL PRIMARY KEY,
+			login VARCHAR(100) NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			min_price NUMERIC(11, 2) NOT NULL DEFAULT 0,
+			max_price NUMERIC(11, 2) NOT NULL DEFAULT 100000000,
+			query TEXT NOT NULL DEFAULT '',
+			category VARCHAR(100) NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_login ON saved_searches (login);
+
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+			saved_search_id INT NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+			advertisement_id INT NOT NULL REFERENCES advertisements(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (saved_search_id, advertisement_id)
+);
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS notifications (
+			id SERIAL PRIMARY KEY,
+			login VARCHAR(100) NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+			type VARCHAR(50) NOT NULL,
+			payload JSONB NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			read_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_login_id ON notifications (login, id DESC);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_notifications_unread;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (login) WHERE read_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS conversations (
+			id SERIAL PRIMARY KEY,
+			advertisement_id INT NOT NULL REFERENCES advertisements(id) ON DELETE CASCADE,
+			buyer VARCHAR(100) NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+			seller VARCHAR(100) NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (advertisement_id, buyer)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversations_buyer_updated_at ON conversations (buyer, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_conversations_seller_updated_at ON conversations (seller, updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS messages (
+			id SERIAL PRIMARY KEY,
+			conversation_id INT NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+			sender VARCHAR(100) NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+			text TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			read_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_conversation_id ON messages (conversation_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_messages_unread ON messages (conversation_id) WHERE read_at IS NULL;
\ No newline at end of file
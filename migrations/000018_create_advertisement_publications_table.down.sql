@@ -0,0 +1 @@
+DROP TABLE IF EXISTS advertisement_publications;
\ No newline at end of file
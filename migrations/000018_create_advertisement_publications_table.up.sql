@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS advertisement_publications (
+			id BIGSERIAL PRIMARY KEY,
+			advertisement_id INT NOT NULL REFERENCES advertisements(id) ON DELETE CASCADE,
+			published_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
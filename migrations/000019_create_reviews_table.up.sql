@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS reviews (
+			id SERIAL PRIMARY KEY,
+			advertisement_id INT REFERENCES advertisements(id) ON DELETE SET NULL,
+			seller VARCHAR(100) NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+			author VARCHAR(100) NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+			rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+			comment TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (advertisement_id, author)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_seller_created_at ON reviews (seller, created_at DESC);
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE users
+	ADD COLUMN IF NOT EXISTS display_name VARCHAR(50) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(500) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS bio TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS city VARCHAR(100) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS phone VARCHAR(20) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS contact_methods TEXT[] NOT NULL DEFAULT '{messages}';
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_advertisements_login_created_at;
+
+DROP TABLE IF EXISTS advertisement_views;
+
+ALTER TABLE advertisements DROP COLUMN IF EXISTS views_count;
\ No newline at end of file
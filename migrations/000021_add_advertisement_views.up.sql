@@ -0,0 +1,12 @@
+ALTER TABLE advertisements ADD COLUMN IF NOT EXISTS views_count INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS advertisement_views (
+			advertisement_id INT NOT NULL REFERENCES advertisements(id) ON DELETE CASCADE,
+			viewer VARCHAR(110) NOT NULL,
+			viewed_on DATE NOT NULL DEFAULT CURRENT_DATE,
+			PRIMARY KEY (advertisement_id, viewer, viewed_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_advertisement_views_viewed_on ON advertisement_views (viewed_on);
+
+CREATE INDEX IF NOT EXISTS idx_advertisements_login_created_at ON advertisements (login, created_at DESC);
\ No newline at end of file
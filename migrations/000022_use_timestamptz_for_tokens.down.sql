@@ -0,0 +1,12 @@
+ALTER TABLE refresh_tokens
+	ALTER COLUMN expires_at TYPE TIMESTAMP,
+	ALTER COLUMN used_at TYPE TIMESTAMP,
+	ALTER COLUMN revoked_at TYPE TIMESTAMP,
+	ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE revoked_tokens
+	ALTER COLUMN expires_at TYPE TIMESTAMP,
+	ALTER COLUMN revoked_at TYPE TIMESTAMP;
+
+ALTER TABLE users
+	ALTER COLUMN tokens_revoked_at TYPE TIMESTAMP;
\ No newline at end of file
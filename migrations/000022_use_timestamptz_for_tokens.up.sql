@@ -0,0 +1,12 @@
+ALTER TABLE users
+	ALTER COLUMN tokens_revoked_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE revoked_tokens
+	ALTER COLUMN expires_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN revoked_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE refresh_tokens
+	ALTER COLUMN expires_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN used_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN revoked_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ;
\ No newline at end of file
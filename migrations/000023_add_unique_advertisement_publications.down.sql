@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_advertisement_publications_advertisement_id;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DELETE FROM advertisement_publications p USING advertisement_publications q WHERE p.advertisement_id = q.advertisement_id AND p.id > q.id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_advertisement_publications_advertisement_id ON advertisement_publications (advertisement_id);
\ No newline at end of file
@@ -5,17 +5,74 @@ import (
 	"github.com/gofiber/swagger"
 	"github.com/vk_intern/handlers"
 	"github.com/vk_intern/internal/config"
+	"github.com/vk_intern/internal/feed"
+	"github.com/vk_intern/internal/matching"
 	"github.com/vk_intern/internal/middleware"
+	"github.com/vk_intern/internal/realtime"
+	"github.com/vk_intern/internal/storage"
+	"github.com/vk_intern/internal/thumbnails"
+	"github.com/vk_intern/internal/users"
+	"github.com/vk_intern/internal/views"
 )
 
-func InitRoutes(app *fiber.App, cfg *config.Config) {
+func InitRoutes(app *fiber.App, cfg *config.Config, keys *middleware.KeySet, store storage.Storage, thumbs *thumbnails.Generator, matcher *matching.Matcher, pub realtime.PubSub, stream *feed.Feed, recorder *views.Recorder) {
 	auth := app.Group("/")
 	auth.Post("/register", handlers.RegisterUser)
-	auth.Post("/login", middleware.AuthMiddleware(cfg.JWT.JWTsecret), handlers.LoginUser(cfg.JWT.JWTsecret))
+	auth.Post("/login", middleware.AuthMiddleware(keys), handlers.LoginUser(keys, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL))
+	auth.Post("/logout", middleware.StrictMiddleware(keys), handlers.Logout)
+	auth.Post("/logout/all", middleware.StrictMiddleware(keys), handlers.LogoutAll)
+	auth.Post("/token/refresh", handlers.RefreshTokens(keys, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL))
 
 	adverts := app.Group("/advertisements")
-	adverts.Post("/", middleware.StrictMiddleware(cfg.JWT.JWTsecret), handlers.CreateAdvertisement)
-	adverts.Get("/", middleware.Middleware(cfg.JWT.JWTsecret), handlers.GetAllAdvertisements)
+	adverts.Post("/", middleware.StrictMiddleware(keys), handlers.CreateAdvertisement(cfg.Moderation.Premoderation, matcher, stream))
+	adverts.Get("/", middleware.Middleware(keys), handlers.GetAllAdvertisements)
+	adverts.Get("/stream", handlers.StreamAdvertisements(stream))
+	adverts.Get("/:id", middleware.Middleware(keys), handlers.GetAdvertisement(recorder))
+	adverts.Patch("/:id", middleware.StrictMiddleware(keys), handlers.UpdateAdvertisement(cfg.Moderation.Premoderation))
+	adverts.Delete("/:id", middleware.StrictMiddleware(keys), handlers.DeleteAdvertisement)
+	adverts.Post("/:id/status", middleware.StrictMiddleware(keys), handlers.ChangeAdvertisementStatus(cfg.Moderation.Premoderation, matcher, stream))
+	adverts.Post("/:id/reports", middleware.StrictMiddleware(keys), handlers.CreateReport(cfg.Reports.HideThreshold))
+	adverts.Put("/:id/favorite", middleware.StrictMiddleware(keys), handlers.AddFavorite)
+	adverts.Delete("/:id/favorite", middleware.StrictMiddleware(keys), handlers.RemoveFavorite)
+	adverts.Post("/:id/messages", middleware.StrictMiddleware(keys), handlers.SendMessage(pub))
+	adverts.Post("/:id/reviews", middleware.StrictMiddleware(keys), handlers.CreateReview)
+
+	usrs := app.Group("/users")
+	usrs.Get("/:login", middleware.Middleware(keys), handlers.GetProfile)
+	usrs.Get("/:login/reviews", handlers.GetReviews)
+
+	me := app.Group("/me", middleware.StrictMiddleware(keys))
+	me.Patch("/", handlers.UpdateProfile)
+	me.Get("/advertisements", handlers.GetMyAdvertisements)
+	me.Get("/favorites", handlers.GetFavorites)
+	me.Get("/conversations", handlers.GetConversations)
+	me.Get("/conversations/:id/messages", handlers.GetMessages)
+	me.Get("/notifications", handlers.GetNotifications)
+	me.Post("/notifications/read", handlers.ReadNotifications)
+	me.Post("/saved-searches", handlers.CreateSavedSearch)
+	me.Get("/saved-searches", handlers.GetSavedSearches)
+	me.Get("/saved-searches/matches", handlers.GetSavedSearchMatches)
+	me.Delete("/saved-searches/:id", handlers.DeleteSavedSearch)
+
+	app.Get("/categories", handlers.GetCategories)
+
+	app.Get("/ws", middleware.WebSocketMiddleware(keys), handlers.Realtime(pub))
+
+	app.Get("/.well-known/jwks.json", handlers.GetJWKS(keys))
+
+	moderation := app.Group("/moderation", middleware.StrictMiddleware(keys), middleware.RequireRole(users.RoleModerator, users.RoleAdmin))
+	moderation.Get("/advertisements", handlers.GetModerationQueue)
+	moderation.Post("/advertisements/:id/approve", handlers.ApproveAdvertisement(matcher, stream))
+	moderation.Post("/advertisements/:id/reject", handlers.RejectAdvertisement)
+	moderation.Post("/advertisements/:id/reports/dismiss", handlers.DismissReports)
+	moderation.Get("/reports", handlers.GetReportedAdvertisements)
+
+	admin := app.Group("/admin", middleware.StrictMiddleware(keys), middleware.RequireRole(users.RoleAdmin))
+	admin.Put("/users/:login/role", handlers.SetUserRole)
+
+	images := app.Group("/images")
+	images.Post("/", middleware.StrictMiddleware(keys), handlers.UploadImage(store, thumbs, cfg.Images.MaxSize, cfg.Images.MaxPixels))
+	images.Get("/:name", handlers.GetImage(store))
 
 	app.Get("/swagger/*", swagger.HandlerDefault) // роут для сваггера
 }